@@ -49,6 +49,19 @@ func (r *TokenRequest) SetTokenParam(key, value string) {
 	r.params.Set(key, value)
 }
 
+// Copy returns a copy of the request, so that options can be added without changing the original
+func (r *TokenRequest) Copy() *TokenRequest {
+	params := make(url.Values, len(r.params))
+	for k, v := range r.params {
+		params[k] = append([]string(nil), v...)
+	}
+	return &TokenRequest{
+		grantType:     r.grantType,
+		params:        params,
+		responseTypes: append([]ResponseType(nil), r.responseTypes...),
+	}
+}
+
 func (r *TokenRequest) WithOption(opt TokenOption) *TokenRequest {
 	opt(r)
 	return r
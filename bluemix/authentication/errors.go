@@ -83,3 +83,22 @@ func NewSessionInactiveError(description string) *SessionInactiveError {
 func (e *SessionInactiveError) Error() string {
 	return T("Session inactive: ") + e.Description
 }
+
+// MFAAttemptsExceededError is an error when the second factor of a login could not be
+// verified within the allowed number of attempts
+type MFAAttemptsExceededError struct {
+	Attempts int
+	Err      error
+}
+
+func NewMFAAttemptsExceededError(attempts int, err error) *MFAAttemptsExceededError {
+	return &MFAAttemptsExceededError{Attempts: attempts, Err: err}
+}
+
+func (e *MFAAttemptsExceededError) Error() string {
+	return T("Multi-factor authentication failed after {{.Attempts}} attempts: ", map[string]interface{}{"Attempts": e.Attempts}) + e.Err.Error()
+}
+
+func (e *MFAAttemptsExceededError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,182 @@
+package iam_test
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/authentication"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/authentication/iam"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/configuration/core_config"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/common/rest"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/testhelpers/identity"
+)
+
+var mockUser = identity.Identity{
+	IAMID:     "IBMid-123",
+	ID:        "IBMid-123",
+	Name:      "Test User",
+	Email:     "user@ibm.com",
+	Subject:   "user@ibm.com",
+	AccountID: "account-123",
+}
+
+func TestFakeServerAPIKeyGrant(t *testing.T) {
+	server := identity.NewServer()
+	defer server.Close()
+	server.AddAPIKey("my-apikey", mockUser)
+
+	client := iam.NewClient(iam.DefaultConfig(server.URL), rest.NewClient())
+	token, err := client.GetToken(iam.APIKeyTokenRequest("my-apikey", iam.SetAccount("account-456")))
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.NotEmpty(t, token.RefreshToken)
+	assert.NotEmpty(t, token.SessionID)
+
+	info := core_config.NewIAMTokenInfo(token.AccessToken)
+	assert.Equal(t, "IBMid-123", info.IAMID)
+	assert.Equal(t, "user@ibm.com", info.UserEmail)
+	assert.Equal(t, "account-456", info.Accounts.AccountID)
+	assert.Equal(t, iam.GrantTypeAPIKey.String(), info.GrantType)
+	assert.False(t, info.HasExpired())
+
+	// the access token is signed with the server key
+	parts := strings.Split(token.AccessToken, ".")
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.Nil(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.Nil(t, rsa.VerifyPKCS1v15(server.PublicKey(), crypto.SHA256, digest[:], signature))
+
+	_, err = client.GetToken(iam.APIKeyTokenRequest("unknown"))
+	assert.IsType(t, &authentication.ServerError{}, err)
+}
+
+func TestFakeServerRefreshTokenGrant(t *testing.T) {
+	server := identity.NewServer()
+	defer server.Close()
+	server.AddAPIKey("my-apikey", mockUser)
+
+	client := iam.NewClient(iam.DefaultConfig(server.URL), rest.NewClient())
+	token, err := client.GetToken(iam.APIKeyTokenRequest("my-apikey"))
+	assert.Nil(t, err)
+
+	refreshed, err := client.GetToken(iam.RefreshTokenRequest(token.RefreshToken))
+	assert.Nil(t, err)
+	assert.Equal(t, "IBMid-123", core_config.NewIAMTokenInfo(refreshed.AccessToken).IAMID)
+
+	server.ExpireRefreshToken(refreshed.RefreshToken)
+	_, err = client.GetToken(iam.RefreshTokenRequest(refreshed.RefreshToken))
+	assert.IsType(t, &authentication.RefreshTokenExpiryError{}, err)
+
+	_, err = client.GetToken(iam.RefreshTokenRequest("unknown"))
+	assert.IsType(t, &authentication.InvalidTokenError{}, err)
+}
+
+func TestFakeServerPasswordGrantWithMFA(t *testing.T) {
+	server := identity.NewServer()
+	defer server.Close()
+	server.AddUser("user@ibm.com", "secret", mockUser)
+	server.RequireMFA("user@ibm.com", identity.MFAChallenge{
+		RequirementCode:    "BXNIM0513E",
+		RequirementMessage: "TOTP verification required",
+		Vendor:             iam.MFAVendorTOTP,
+		SecurityCode:       "123456",
+	})
+
+	client := iam.NewClient(iam.DefaultConfig(server.URL), rest.NewClient())
+	_, err := client.GetToken(iam.PasswordTokenRequest("user@ibm.com", "secret"))
+	extErr, ok := err.(*authentication.ExternalAuthenticationError)
+	assert.True(t, ok)
+	assert.Equal(t, "BXNIM0513E", extErr.ErrorCode)
+	assert.Equal(t, "TOTP verification required", extErr.ErrorMessage)
+
+	_, err = client.GetToken(iam.PasswordTokenRequest("user@ibm.com", "secret", iam.SetTOTPCode("000000")))
+	assert.IsType(t, &authentication.ExternalAuthenticationError{}, err)
+
+	token, err := client.GetToken(iam.PasswordTokenRequest("user@ibm.com", "secret", iam.SetTOTPCode("123456")))
+	assert.Nil(t, err)
+	assert.Equal(t, iam.GrantTypePassword.String(), core_config.NewIAMTokenInfo(token.AccessToken).GrantType)
+}
+
+func TestFakeServerPhoneFactor(t *testing.T) {
+	server := identity.NewServer()
+	defer server.Close()
+	server.AddUser("user@ibm.com", "secret", mockUser)
+	server.RequireMFA("user@ibm.com", identity.MFAChallenge{
+		RequirementCode: "BXNIM0514E",
+		Vendor:          iam.MFAVendorPhoneFactor,
+	})
+
+	client := iam.NewClient(iam.DefaultConfig(server.URL), rest.NewClient())
+	authToken, err := client.InitiateIMSPhoneFactor(iam.PasswordTokenRequest("user@ibm.com", "secret"))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, authToken)
+
+	_, err = client.GetToken(iam.PasswordTokenRequest("user@ibm.com", "secret", iam.SetPhoneAuthToken(authToken)))
+	assert.Nil(t, err)
+
+	// the phone factor token can only be used once
+	_, err = client.GetToken(iam.PasswordTokenRequest("user@ibm.com", "secret", iam.SetPhoneAuthToken(authToken)))
+	assert.NotNil(t, err)
+}
+
+func TestFakeServerCRTokenGrant(t *testing.T) {
+	server := identity.NewServer()
+	defer server.Close()
+	profile := identity.Identity{
+		IAMID:       "iam-Profile-123",
+		ID:          "Profile-123",
+		Name:        "my-profile",
+		SubjectType: core_config.SubjectTypeTrustedProfile,
+		AccountID:   "account-123",
+		Authn:       &identity.Identity{Subject: "crn:v1:bluemix:public:iam-identity::a/account-123::computeresource:cr-1"},
+	}
+	server.AddCRToken("cr-token", profile)
+
+	client := iam.NewClient(iam.DefaultConfig(server.URL), rest.NewClient())
+	token, err := client.GetToken(iam.CRTokenRequest("cr-token", "", "my-profile"))
+	assert.Nil(t, err)
+	info := core_config.NewIAMTokenInfo(token.AccessToken)
+	assert.Equal(t, core_config.SubjectTypeTrustedProfile, info.SubjectType)
+	assert.Equal(t, profile.Authn.Subject, info.Authn.Subject)
+
+	_, err = client.GetToken(iam.CRTokenRequest("cr-token", "Profile-456", ""))
+	assert.NotNil(t, err)
+}
+
+func TestFakeServerRefreshSession(t *testing.T) {
+	server := identity.NewServer()
+	defer server.Close()
+	server.AddAuthorizationCode("code", "http://localhost/callback", mockUser)
+
+	client := iam.NewClient(iam.DefaultConfig(server.URL), rest.NewClient())
+	token, err := client.GetToken(iam.AuthorizationTokenRequest("code", "http://localhost/callback"))
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.RefreshSession(token.SessionID))
+
+	server.ExpireSession(token.SessionID)
+	err = client.RefreshSession(token.SessionID)
+	assert.IsType(t, &authentication.SessionInactiveError{}, err)
+}
+
+func TestFakeServerFailGrant(t *testing.T) {
+	server := identity.NewServer()
+	defer server.Close()
+	server.AddAPIKey("my-apikey", mockUser)
+	server.FailGrant(iam.GrantTypeAPIKey, http.StatusInternalServerError, iam.APIError{ErrorCode: "BXNIM0100E", ErrorMessage: "unavailable"})
+
+	client := iam.NewClient(iam.DefaultConfig(server.URL), rest.NewClient())
+	_, err := client.GetToken(iam.APIKeyTokenRequest("my-apikey"))
+	serverErr, ok := err.(*authentication.ServerError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, serverErr.StatusCode)
+	assert.Equal(t, "BXNIM0100E", serverErr.ErrorCode)
+	assert.Len(t, server.TokenRequests(), 1)
+}
@@ -0,0 +1,202 @@
+package iam
+
+import (
+	"strings"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/authentication"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/terminal"
+	. "github.com/IBM-Cloud/ibm-cloud-cli-sdk/i18n"
+)
+
+const defaultMFAMaxAttempts = 3
+
+// MFAFactor is the kind of second factor IAM requires to complete a login
+type MFAFactor string
+
+func (f MFAFactor) String() string {
+	return string(f)
+}
+
+// MFA factors
+const (
+	MFAFactorUnknown          MFAFactor = ""
+	MFAFactorTOTP             MFAFactor = "TOTP"
+	MFAFactorVerisign         MFAFactor = "VERISIGN"
+	MFAFactorPhone            MFAFactor = "PHONE_FACTOR"
+	MFAFactorSecurityQuestion MFAFactor = "SECURITY_QUESTION"
+)
+
+// MFAChallenge is the second factor requested by IAM when a token request fails with an
+// external authentication error
+type MFAChallenge struct {
+	Factor      MFAFactor
+	Requirement Requirement
+	// Attempt is the 1-based number of the current attempt to resolve the challenge
+	Attempt int
+}
+
+// MFAAnswer is the answer to an MFAChallenge
+type MFAAnswer struct {
+	// Code is the security code for TOTP and Verisign challenges
+	Code string
+	// QuestionID and QuestionAnswer answer a security question challenge
+	QuestionID     int
+	QuestionAnswer string
+}
+
+// MFAPromptFunc returns the answer to the challenge. Phone factor challenges require no answer,
+// the function returns once the user approved the request on the phone.
+type MFAPromptFunc func(challenge MFAChallenge) (MFAAnswer, error)
+
+// MFAClassifyFunc maps the requirement of an external authentication error to an MFA factor
+type MFAClassifyFunc func(requirement Requirement) MFAFactor
+
+// MFAResolver retries a token request with the second factor requested by IAM
+type MFAResolver struct {
+	Client Interface
+	Prompt MFAPromptFunc
+	// Classify is optional. The default classifies the requirement by its code and message.
+	Classify MFAClassifyFunc
+	// MaxAttempts is optional. The default is 3.
+	MaxAttempts int
+}
+
+// NewMFAResolver returns a resolver that prompts for the second factor through the terminal UI
+func NewMFAResolver(client Interface, ui terminal.UI) *MFAResolver {
+	return &MFAResolver{
+		Client: client,
+		Prompt: UIMFAPrompt(ui),
+	}
+}
+
+// GetToken requests a token. If IAM requires a second factor, the resolver prompts for it and
+// retries the request with the corresponding option until it succeeds, IAM returns a different
+// error or the maximum number of attempts is reached.
+func (r *MFAResolver) GetToken(req *authentication.TokenRequest) (*Token, error) {
+	maxAttempts := r.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMFAMaxAttempts
+	}
+	classify := r.Classify
+	if classify == nil {
+		classify = ClassifyMFARequirement
+	}
+
+	token, err := r.Client.GetToken(req)
+	for attempt := 1; err != nil; attempt++ {
+		requirement, ok := mfaRequirement(err)
+		if !ok {
+			return nil, err
+		}
+		if attempt > maxAttempts {
+			return nil, authentication.NewMFAAttemptsExceededError(maxAttempts, err)
+		}
+
+		challenge := MFAChallenge{
+			Factor:      classify(requirement),
+			Requirement: requirement,
+			Attempt:     attempt,
+		}
+		if challenge.Factor == MFAFactorUnknown {
+			return nil, err
+		}
+
+		opt, promptErr := r.resolve(req, challenge)
+		if promptErr != nil {
+			return nil, promptErr
+		}
+		// each attempt starts from the original request, which is left unchanged for the caller
+		token, err = r.Client.GetToken(req.Copy().WithOption(opt))
+	}
+	return token, nil
+}
+
+func (r *MFAResolver) resolve(req *authentication.TokenRequest, challenge MFAChallenge) (authentication.TokenOption, error) {
+	if challenge.Factor == MFAFactorPhone {
+		authToken, err := r.Client.InitiateIMSPhoneFactor(req)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.Prompt(challenge); err != nil {
+			return nil, err
+		}
+		return SetPhoneAuthToken(authToken), nil
+	}
+
+	answer, err := r.Prompt(challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	switch challenge.Factor {
+	case MFAFactorTOTP:
+		return SetTOTPCode(answer.Code), nil
+	case MFAFactorVerisign:
+		return SetVeriSignCode(answer.Code), nil
+	default:
+		return SetSecurityQuestion(answer.QuestionID, answer.QuestionAnswer), nil
+	}
+}
+
+func mfaRequirement(err error) (Requirement, bool) {
+	switch e := err.(type) {
+	case *authentication.ExternalAuthenticationError:
+		return Requirement{ErrorCode: e.ErrorCode, ErrorMessage: e.ErrorMessage}, true
+	case authentication.ExternalAuthenticationError:
+		return Requirement{ErrorCode: e.ErrorCode, ErrorMessage: e.ErrorMessage}, true
+	}
+	return Requirement{}, false
+}
+
+// ClassifyMFARequirement is the default MFAClassifyFunc. It looks for the name of the
+// factor in the requirement code and message.
+func ClassifyMFARequirement(requirement Requirement) MFAFactor {
+	s := strings.ToLower(requirement.ErrorCode + " " + requirement.ErrorMessage)
+	switch {
+	case strings.Contains(s, "totp"):
+		return MFAFactorTOTP
+	case strings.Contains(s, "verisign") || strings.Contains(s, "symantec"):
+		return MFAFactorVerisign
+	case strings.Contains(s, "phone"):
+		return MFAFactorPhone
+	case strings.Contains(s, "security question"):
+		return MFAFactorSecurityQuestion
+	}
+	return MFAFactorUnknown
+}
+
+// UIMFAPrompt returns an MFAPromptFunc that asks for the answer through the terminal UI
+func UIMFAPrompt(ui terminal.UI) MFAPromptFunc {
+	return func(challenge MFAChallenge) (MFAAnswer, error) {
+		var answer MFAAnswer
+
+		if challenge.Requirement.ErrorMessage != "" {
+			ui.Info("%s", challenge.Requirement.ErrorMessage)
+		}
+
+		switch challenge.Factor {
+		case MFAFactorTOTP:
+			err := ui.Prompt(T("Enter the code from your authenticator app"),
+				&terminal.PromptOptions{Required: true, HideInput: true}).Resolve(&answer.Code)
+			return answer, err
+		case MFAFactorVerisign:
+			err := ui.Prompt(T("Enter the code from your Verisign token"),
+				&terminal.PromptOptions{Required: true, HideInput: true}).Resolve(&answer.Code)
+			return answer, err
+		case MFAFactorPhone:
+			var ignored string
+			err := ui.Prompt(T("Approve the login request on your phone, then press Enter to continue"),
+				&terminal.PromptOptions{HideDefault: true}).Resolve(&ignored)
+			return answer, err
+		case MFAFactorSecurityQuestion:
+			if err := ui.Prompt(T("Security question ID"),
+				&terminal.PromptOptions{Required: true}).Resolve(&answer.QuestionID); err != nil {
+				return answer, err
+			}
+			err := ui.Prompt(T("Answer"),
+				&terminal.PromptOptions{Required: true, HideInput: true}).Resolve(&answer.QuestionAnswer)
+			return answer, err
+		}
+		return answer, nil
+	}
+}
@@ -0,0 +1,131 @@
+package iam_test
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/authentication"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/authentication/iam"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/common/rest"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/testhelpers/identity"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/testhelpers/terminal"
+)
+
+func startMFAServer(challenge identity.MFAChallenge) (*identity.Server, iam.Interface) {
+	server := identity.NewServer()
+	server.AddUser("user@ibm.com", "secret", mockUser)
+	server.RequireMFA("user@ibm.com", challenge)
+	return server, iam.NewClient(iam.DefaultConfig(server.URL), rest.NewClient())
+}
+
+func TestClassifyMFARequirement(t *testing.T) {
+	assert.Equal(t, iam.MFAFactorTOTP, iam.ClassifyMFARequirement(iam.Requirement{ErrorMessage: "TOTP verification required"}))
+	assert.Equal(t, iam.MFAFactorVerisign, iam.ClassifyMFARequirement(iam.Requirement{ErrorMessage: "Verisign code required"}))
+	assert.Equal(t, iam.MFAFactorPhone, iam.ClassifyMFARequirement(iam.Requirement{ErrorMessage: "Phone factor verification required"}))
+	assert.Equal(t, iam.MFAFactorSecurityQuestion, iam.ClassifyMFARequirement(iam.Requirement{ErrorMessage: "Answer the security question"}))
+	assert.Equal(t, iam.MFAFactorUnknown, iam.ClassifyMFARequirement(iam.Requirement{ErrorMessage: "Account is locked"}))
+}
+
+func TestMFAResolverTOTPWithUI(t *testing.T) {
+	server, client := startMFAServer(identity.MFAChallenge{
+		RequirementMessage: "TOTP verification required",
+		Vendor:             iam.MFAVendorTOTP,
+		SecurityCode:       "123456",
+	})
+	defer server.Close()
+
+	ui := terminal.NewFakeUI()
+	ui.Inputs("000000", "123456")
+
+	req := iam.PasswordTokenRequest("user@ibm.com", "secret")
+	token, err := iam.NewMFAResolver(client, ui).GetToken(req)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token.AccessToken)
+	assert.Empty(t, req.GetTokenParam("security_code"), "the request of the caller must not be changed")
+	assert.Len(t, ui.PasswordPrompts, 2)
+	assert.Contains(t, ui.Errors(), "TOTP verification required")
+	assert.Len(t, server.TokenRequests(), 3)
+}
+
+func TestMFAResolverSecurityQuestion(t *testing.T) {
+	server, client := startMFAServer(identity.MFAChallenge{
+		RequirementMessage:     "Answer the security question",
+		SecurityQuestionID:     2,
+		SecurityQuestionAnswer: "blue",
+	})
+	defer server.Close()
+
+	resolver := &iam.MFAResolver{
+		Client: client,
+		Prompt: func(challenge iam.MFAChallenge) (iam.MFAAnswer, error) {
+			assert.Equal(t, iam.MFAFactorSecurityQuestion, challenge.Factor)
+			return iam.MFAAnswer{QuestionID: 2, QuestionAnswer: "blue"}, nil
+		},
+	}
+	_, err := resolver.GetToken(iam.PasswordTokenRequest("user@ibm.com", "secret"))
+	assert.Nil(t, err)
+}
+
+func TestMFAResolverPhoneFactor(t *testing.T) {
+	server, client := startMFAServer(identity.MFAChallenge{
+		RequirementMessage: "Phone factor verification required",
+		Vendor:             iam.MFAVendorPhoneFactor,
+	})
+	defer server.Close()
+
+	ui := terminal.NewFakeUI()
+	ui.Inputs("")
+
+	_, err := iam.NewMFAResolver(client, ui).GetToken(iam.PasswordTokenRequest("user@ibm.com", "secret"))
+	assert.Nil(t, err)
+	assert.Len(t, ui.Prompts, 1)
+}
+
+func TestMFAResolverMaxAttempts(t *testing.T) {
+	server, client := startMFAServer(identity.MFAChallenge{
+		RequirementMessage: "TOTP verification required",
+		Vendor:             iam.MFAVendorTOTP,
+		SecurityCode:       "123456",
+	})
+	defer server.Close()
+
+	var attempts []int
+	resolver := &iam.MFAResolver{
+		Client:      client,
+		MaxAttempts: 2,
+		Prompt: func(challenge iam.MFAChallenge) (iam.MFAAnswer, error) {
+			attempts = append(attempts, challenge.Attempt)
+			return iam.MFAAnswer{Code: "000000"}, nil
+		},
+	}
+	_, err := resolver.GetToken(iam.PasswordTokenRequest("user@ibm.com", "secret"))
+	assert.IsType(t, &authentication.MFAAttemptsExceededError{}, err)
+	assert.Equal(t, []int{1, 2}, attempts)
+
+	var extErr *authentication.ExternalAuthenticationError
+	assert.True(t, errors.As(err, &extErr))
+}
+
+func TestMFAResolverOtherErrors(t *testing.T) {
+	server, client := startMFAServer(identity.MFAChallenge{
+		RequirementMessage: "TOTP verification required",
+		Vendor:             iam.MFAVendorTOTP,
+	})
+	defer server.Close()
+
+	promptErr := errors.New("cancelled")
+	resolver := &iam.MFAResolver{
+		Client: client,
+		Prompt: func(challenge iam.MFAChallenge) (iam.MFAAnswer, error) {
+			return iam.MFAAnswer{}, promptErr
+		},
+	}
+	_, err := resolver.GetToken(iam.PasswordTokenRequest("user@ibm.com", "secret"))
+	assert.Equal(t, promptErr, err)
+
+	// errors other than external authentication errors are returned without prompting
+	_, err = resolver.GetToken(iam.PasswordTokenRequest("user@ibm.com", "wrong"))
+	assert.IsType(t, &authentication.ServerError{}, err)
+}
@@ -46,6 +46,16 @@ func ConfigFilePath() string {
 	return filepath.Join(ConfigDir(), "config.json")
 }
 
+// EndpointsOverridesFilePath returns the path of the file overriding service endpoints
+func EndpointsOverridesFilePath() string {
+	return filepath.Join(ConfigDir(), "endpoints.json")
+}
+
+// HTTPCacheDir returns the directory of the HTTP response cache
+func HTTPCacheDir() string {
+	return filepath.Join(ConfigDir(), "http_cache")
+}
+
 func PluginRepoDir() string {
 	return filepath.Join(ConfigDir(), "plugins")
 }
@@ -47,6 +47,9 @@ type BXConfigData struct {
 	LoginAt                   time.Time
 	PluginRepos               []models.PluginRepo
 	SSLDisabled               bool
+	CACertFiles               []string
+	ClientCertFile            string
+	ClientKeyFile             string
 	Locale                    string
 	MessageOfTheDayTime       int64
 	LastSessionUpdateTime     int64
@@ -205,6 +208,27 @@ func (c *bxConfig) IsSSLDisabled() (disabled bool) {
 	return
 }
 
+func (c *bxConfig) CACertFiles() (files []string) {
+	c.read(func() {
+		files = append(files, c.data.CACertFiles...)
+	})
+	return
+}
+
+func (c *bxConfig) ClientCertFile() (file string) {
+	c.read(func() {
+		file = c.data.ClientCertFile
+	})
+	return
+}
+
+func (c *bxConfig) ClientKeyFile() (file string) {
+	c.read(func() {
+		file = c.data.ClientKeyFile
+	})
+	return
+}
+
 func (c *bxConfig) ConsoleEndpoints() (endpoints models.Endpoints) {
 	c.read(func() {
 		endpoints.PublicEndpoint = c.data.ConsoleEndpoint
@@ -665,6 +689,19 @@ func (c *bxConfig) SetSSLDisabled(disabled bool) {
 	})
 }
 
+func (c *bxConfig) SetCACertFiles(files []string) {
+	c.write(func() {
+		c.data.CACertFiles = files
+	})
+}
+
+func (c *bxConfig) SetClientCertificate(certFile string, keyFile string) {
+	c.write(func() {
+		c.data.ClientCertFile = certFile
+		c.data.ClientKeyFile = keyFile
+	})
+}
+
 func (c *bxConfig) SetHTTPTimeout(timeout int) {
 	c.write(func() {
 		c.data.HTTPTimeout = timeout
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/authentication/vpc"
 	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/configuration/core_config"
 	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/models"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/testhelpers/metadata"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -286,6 +288,22 @@ func TestCRIType(t *testing.T) {
 	t.Cleanup(cleanupConfigFiles)
 }
 
+func TestTLSSettings(t *testing.T) {
+	config := prepareConfigForCLI(`{"UsageStatsEnabledLastUpdate": "2020-03-29T12:23:43.519017+08:00","UsageStatsEnabled": true}`, t)
+
+	assert.Empty(t, config.CACertFiles())
+	assert.Empty(t, config.ClientCertFile())
+	assert.Empty(t, config.ClientKeyFile())
+
+	config.SetCACertFiles([]string{"/certs/proxy-ca.pem"})
+	config.SetClientCertificate("/certs/client.crt", "/certs/client.key")
+	assert.Equal(t, []string{"/certs/proxy-ca.pem"}, config.CACertFiles())
+	assert.Equal(t, "/certs/client.crt", config.ClientCertFile())
+	assert.Equal(t, "/certs/client.key", config.ClientKeyFile())
+
+	t.Cleanup(cleanupConfigFiles)
+}
+
 func TestVPCCRITokenURL(t *testing.T) {
 	config := prepareConfigForCLI(`{"UsageStatsEnabledLastUpdate": "2020-03-29T12:23:43.519017+08:00","UsageStatsEnabled": true}`, t)
 
@@ -407,6 +425,64 @@ func TestIsLoggedInAsCRI(t *testing.T) {
 	t.Cleanup(cleanupConfigFiles)
 }
 
+func TestRefreshIAMTokenWithVPCAuth(t *testing.T) {
+	server := metadata.NewVPCServer()
+	defer server.Close()
+	server.SetIAMToken("vpc-iam-token", 3600)
+
+	oldValue := bluemix.EnvCRVpcUrl.Get()
+	bluemix.EnvCRVpcUrl.Set(server.URL)
+	defer bluemix.EnvCRVpcUrl.Set(oldValue)
+
+	config := prepareConfigForCLI(`{"UsageStatsEnabledLastUpdate": "2020-03-29T12:23:43.519017+08:00","UsageStatsEnabled": true}`, t)
+	config.SetIsLoggedInAsCRI(true)
+	config.SetCRIType("VPC")
+	config.SetProfile(models.Profile{ID: "mock_profile_ID"})
+
+	token, err := config.RefreshIAMToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer vpc-iam-token", token)
+	assert.Equal(t, "Bearer vpc-iam-token", config.IAMToken())
+	assert.Equal(t, 1, server.IdentityTokenCallCount())
+	assert.Equal(t, 1, server.IAMTokenCallCount())
+	assert.Equal(t, "mock_profile_ID", server.LastTrustedProfile().ID)
+
+	// failures of the metadata service are returned to the caller
+	server.FailIAMToken(&metadata.Error{StatusCode: http.StatusUnauthorized, Code: "not_authorized", Message: "profile not linked"})
+	_, err = config.RefreshIAMToken()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "profile not linked")
+	assert.Equal(t, "Bearer vpc-iam-token", config.IAMToken())
+
+	t.Cleanup(cleanupConfigFiles)
+}
+
+func TestRefreshIAMTokenWithPVSAuth(t *testing.T) {
+	server := metadata.NewPowerVSServer()
+	defer server.Close()
+	server.SetIAMToken("pvs-iam-token", 3600)
+
+	oldValue := bluemix.EnvCRPvsUrl.Get()
+	bluemix.EnvCRPvsUrl.Set(server.URL)
+	defer bluemix.EnvCRPvsUrl.Set(oldValue)
+
+	config := prepareConfigForCLI(`{"UsageStatsEnabledLastUpdate": "2020-03-29T12:23:43.519017+08:00","UsageStatsEnabled": true}`, t)
+	config.SetIsLoggedInAsCRI(true)
+	config.SetCRIType("PVS")
+
+	// a targeted trusted profile is required
+	_, err := config.RefreshIAMToken()
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, server.IAMTokenCallCount())
+
+	config.SetProfile(models.Profile{ID: "mock_profile_ID"})
+	token, err := config.RefreshIAMToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer pvs-iam-token", token)
+
+	t.Cleanup(cleanupConfigFiles)
+}
+
 func TestClearSession(t *testing.T) {
 	config := prepareConfigForCLI(`{"UsageStatsEnabledLastUpdate": "2020-03-29T12:23:43.519017+08:00","UsageStatsEnabled": true}`, t)
 
@@ -554,8 +630,9 @@ func checkUsageStats(enabled bool, timeStampExist bool, config core_config.Repos
 }
 
 func prepareConfigForCLI(cliConfigContent string, t *testing.T) core_config.Repository {
-	os.WriteFile("config.json", []byte(cliConfigContent), 0644)
-	return core_config.NewCoreConfigFromPath("config.json", func(err error) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(cliConfigContent), 0644)
+	return core_config.NewCoreConfigFromPath(path, func(err error) {
 		t.Fatal(err.Error())
 	})
 }
@@ -51,6 +51,11 @@ type Repository interface {
 	PluginRepos() []models.PluginRepo
 	PluginRepo(string) (models.PluginRepo, bool)
 	IsSSLDisabled() bool
+	// CACertFiles returns the PEM files of the CA certificates trusted in addition to the system ones
+	CACertFiles() []string
+	// ClientCertFile and ClientKeyFile return the PEM files of the client certificate and key used for mutual TLS
+	ClientCertFile() string
+	ClientKeyFile() string
 	TypeOfSSO() string
 	AlphaCommandsEnabled() string
 	AssumedTrustedProfileId() string
@@ -111,6 +116,8 @@ type Repository interface {
 	SetPluginRepo(models.PluginRepo)
 	UnsetPluginRepo(string)
 	SetSSLDisabled(bool)
+	SetCACertFiles([]string)
+	SetClientCertificate(certFile string, keyFile string)
 	SetTypeOfSSO(string)
 	SetHTTPTimeout(int)
 	// SetUsageSatsDisabled disable or enable usage statistics data collection
@@ -0,0 +1,184 @@
+package crn
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	CloudTypePublic    = "public"
+	CloudTypeDedicated = "dedicated"
+	CloudTypeLocal     = "local"
+
+	ScopeGlobal = "global"
+)
+
+var (
+	// segmentPattern applies to the cloud name, cloud type, service name, region and resource type
+	segmentPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	// valuePattern applies to the scope, service instance and resource which are free form identifiers
+	valuePattern = regexp.MustCompile(`^[^:\s]+$`)
+
+	scopeTypes = []string{ScopeAccount, ScopeOrganization, ScopeSpace, ScopeProject}
+)
+
+// SegmentError is returned when a segment of a CRN has an invalid value
+type SegmentError struct {
+	Segment string
+	Value   string
+	Reason  string
+}
+
+func (e *SegmentError) Error() string {
+	return fmt.Sprintf("invalid CRN segment '%s' with value '%s': %s", e.Segment, e.Value, e.Reason)
+}
+
+// Validate checks the content of each segment. Empty segments are valid since they
+// are used as wildcards when matching.
+func (c CRN) Validate() error {
+	if c.Scheme != crn {
+		return &SegmentError{Segment: "scheme", Value: c.Scheme, Reason: "must be '" + crn + "'"}
+	}
+	if c.Version != version {
+		return &SegmentError{Segment: "version", Value: c.Version, Reason: "must be '" + version + "'"}
+	}
+
+	for _, s := range []struct{ name, value string }{
+		{"cname", c.CName},
+		{"ctype", c.CType},
+		{"service-name", c.ServiceName},
+		{"location", c.Region},
+		{"resource-type", c.ResourceType},
+	} {
+		if s.value != "" && !segmentPattern.MatchString(s.value) {
+			return &SegmentError{Segment: s.name, Value: s.value, Reason: "must contain only lowercase letters, digits, '.', '_' and '-'"}
+		}
+	}
+
+	if err := validateScope(c.ScopeType, c.Scope); err != nil {
+		return err
+	}
+
+	for _, s := range []struct{ name, value string }{
+		{"service-instance", c.ServiceInstance},
+		{"resource", c.Resource},
+	} {
+		if s.value != "" && !valuePattern.MatchString(s.value) {
+			return &SegmentError{Segment: s.name, Value: s.value, Reason: "must not contain ':' or whitespace"}
+		}
+	}
+
+	if c.Resource != "" && c.ResourceType == "" {
+		return &SegmentError{Segment: "resource-type", Reason: "is required when a resource is set"}
+	}
+	return nil
+}
+
+func validateScope(scopeType, scope string) error {
+	if scopeType == "" {
+		if scope != "" && scope != ScopeGlobal {
+			return &SegmentError{Segment: "scope", Value: scope, Reason: "must be 'global' or have a scope type"}
+		}
+		return nil
+	}
+
+	valid := false
+	for _, t := range scopeTypes {
+		if scopeType == t {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return &SegmentError{Segment: "scope", Value: scopeType, Reason: "scope type must be one of " + strings.Join(scopeTypes, ", ")}
+	}
+	if !valuePattern.MatchString(scope) || strings.Contains(scope, scopeSeparator) {
+		return &SegmentError{Segment: "scope", Value: scope, Reason: "must not be empty or contain ':', '/' or whitespace"}
+	}
+	return nil
+}
+
+// Builder builds a CRN segment by segment. Segments that are not set remain empty.
+// Errors of individual segments are collected and returned by Build.
+type Builder struct {
+	crn  CRN
+	errs []error
+}
+
+// NewBuilder returns a builder for a CRN of the given cloud name and type
+func NewBuilder(cloudName string, cloudType string) *Builder {
+	return &Builder{crn: New(cloudName, cloudType)}
+}
+
+// From returns a builder initialized with the segments of an existing CRN
+func From(c CRN) *Builder {
+	return &Builder{crn: c}
+}
+
+func (b *Builder) check(segment, value string, pattern *regexp.Regexp, reason string) {
+	if value != "" && !pattern.MatchString(value) {
+		b.errs = append(b.errs, &SegmentError{Segment: segment, Value: value, Reason: reason})
+	}
+}
+
+// ServiceName sets the service name segment
+func (b *Builder) ServiceName(serviceName string) *Builder {
+	b.check("service-name", serviceName, segmentPattern, "must contain only lowercase letters, digits, '.', '_' and '-'")
+	b.crn.ServiceName = serviceName
+	return b
+}
+
+// Region sets the location segment
+func (b *Builder) Region(region string) *Builder {
+	b.check("location", region, segmentPattern, "must contain only lowercase letters, digits, '.', '_' and '-'")
+	b.crn.Region = region
+	return b
+}
+
+// Scope sets the scope segment, e.g. Scope(ScopeAccount, accountID)
+func (b *Builder) Scope(scopeType string, scope string) *Builder {
+	if err := validateScope(scopeType, scope); err != nil {
+		b.errs = append(b.errs, err)
+	}
+	b.crn.ScopeType = scopeType
+	b.crn.Scope = scope
+	return b
+}
+
+// Account sets the scope segment to the account
+func (b *Builder) Account(accountID string) *Builder {
+	return b.Scope(ScopeAccount, accountID)
+}
+
+// Global sets the scope segment to 'global'
+func (b *Builder) Global() *Builder {
+	return b.Scope("", ScopeGlobal)
+}
+
+// ServiceInstance sets the service instance segment
+func (b *Builder) ServiceInstance(serviceInstance string) *Builder {
+	b.check("service-instance", serviceInstance, valuePattern, "must not contain ':' or whitespace")
+	b.crn.ServiceInstance = serviceInstance
+	return b
+}
+
+// Resource sets the resource type and resource segments
+func (b *Builder) Resource(resourceType string, resource string) *Builder {
+	b.check("resource-type", resourceType, segmentPattern, "must contain only lowercase letters, digits, '.', '_' and '-'")
+	b.check("resource", resource, valuePattern, "must not contain ':' or whitespace")
+	b.crn.ResourceType = resourceType
+	b.crn.Resource = resource
+	return b
+}
+
+// Build returns the CRN or the first segment error
+func (b *Builder) Build() (CRN, error) {
+	if len(b.errs) > 0 {
+		return CRN{}, b.errs[0]
+	}
+	if err := b.crn.Validate(); err != nil {
+		return CRN{}, err
+	}
+	return b.crn, nil
+}
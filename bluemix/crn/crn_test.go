@@ -41,3 +41,89 @@ func (suite *CRNTestSuite) TestParse() {
 	_, err = Parse(crnString)
 	suite.Error(err)
 }
+
+func (suite *CRNTestSuite) TestBuilder() {
+	crn, err := NewBuilder(ServiceBluemix, CloudTypePublic).
+		ServiceName("cloud-object-storage").
+		Region("us-south").
+		Account("account-guid").
+		ServiceInstance("instance-guid").
+		Resource("bucket", "my-bucket").
+		Build()
+	suite.NoError(err)
+	suite.Equal("crn:v1:bluemix:public:cloud-object-storage:us-south:a/account-guid:instance-guid:bucket:my-bucket", crn.String())
+
+	crn, err = NewBuilder(ServiceBluemix, CloudTypePublic).ServiceName("iam").Global().Build()
+	suite.NoError(err)
+	suite.Equal("crn:v1:bluemix:public:iam::global:::", crn.String())
+
+	_, err = NewBuilder(ServiceBluemix, CloudTypePublic).ServiceName("Cloud Object Storage").Build()
+	suite.Error(err)
+	suite.Equal("service-name", err.(*SegmentError).Segment)
+
+	_, err = NewBuilder(ServiceBluemix, CloudTypePublic).Scope("x", "account-guid").Build()
+	suite.Error(err)
+
+	_, err = NewBuilder(ServiceBluemix, CloudTypePublic).ServiceInstance("a:b").Build()
+	suite.Error(err)
+
+	_, err = NewBuilder(ServiceBluemix, CloudTypePublic).Resource("", "my-bucket").Build()
+	suite.Error(err)
+
+	crn, err = From(crn).Region("eu-de").Build()
+	suite.NoError(err)
+	suite.Equal("eu-de", crn.Region)
+}
+
+func (suite *CRNTestSuite) TestValidate() {
+	crn, err := Parse("crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:instance-guid::")
+	suite.NoError(err)
+	suite.NoError(crn.Validate())
+
+	crn.Version = "v2"
+	suite.Error(crn.Validate())
+}
+
+func (suite *CRNTestSuite) TestMatches() {
+	crn, err := Parse("crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:instance-guid:bucket:my-bucket")
+	suite.NoError(err)
+
+	for pattern, expected := range map[string]bool{
+		"crn:v1:bluemix:public:cloud-object-storage:::::":                                             true,
+		"crn:v1:bluemix:public:cloud-object-storage::a/account-guid:::":                               true,
+		"crn:v1:bluemix:public:::a/:::":                                                               true,
+		"crn:v1:bluemix:public:cloud-object-storage::a/other-account:::":                              false,
+		"crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:instance-guid::":            true,
+		"crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:instance-guid:bucket:other": false,
+		"crn:v1:bluemix:public:iam:::::":                                                              false,
+		"crn:v1:bluemix:dedicated::::::":                                                              false,
+	} {
+		matched, err := crn.MatchesString(pattern)
+		suite.NoError(err)
+		suite.Equal(expected, matched, pattern)
+	}
+
+	_, err = crn.MatchesString("crn:v1")
+	suite.Equal(ErrMalformedCRN, err)
+}
+
+func (suite *CRNTestSuite) TestParentAndChild() {
+	crn, err := Parse("crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:instance-guid:bucket:my-bucket")
+	suite.NoError(err)
+	suite.True(crn.IsResource())
+
+	instance, ok := crn.Parent()
+	suite.True(ok)
+	suite.False(instance.IsResource())
+	suite.Equal("crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:instance-guid::", instance.String())
+	suite.True(crn.Matches(instance))
+
+	service, ok := instance.Parent()
+	suite.True(ok)
+	suite.Equal("crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:::", service.String())
+
+	_, ok = service.Parent()
+	suite.False(ok)
+
+	suite.Equal(crn, instance.Child("bucket", "my-bucket"))
+}
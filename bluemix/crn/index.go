@@ -0,0 +1,193 @@
+package crn
+
+// Index is a set of CRN patterns organized as a trie keyed by segment. It answers which
+// patterns match a CRN without comparing the CRN against every pattern. Empty segments of a
+// pattern are wildcards, with the same semantics as CRN.Matches.
+// An Index is not safe for concurrent use.
+type Index struct {
+	root *indexNode
+	size int
+}
+
+type indexNode struct {
+	children map[string]*indexNode
+	wildcard *indexNode
+	// pattern is set on the leaf node of an inserted pattern
+	pattern *CRN
+}
+
+// NewIndex returns an index containing the given patterns
+func NewIndex(patterns ...CRN) *Index {
+	idx := &Index{root: &indexNode{}}
+	for _, p := range patterns {
+		idx.Insert(p)
+	}
+	return idx
+}
+
+func indexSegments(c CRN) []string {
+	return []string{
+		c.Scheme,
+		c.Version,
+		c.CName,
+		c.CType,
+		c.ServiceName,
+		c.Region,
+		c.ScopeType,
+		c.Scope,
+		c.ServiceInstance,
+		c.ResourceType,
+		c.Resource,
+	}
+}
+
+// Len returns the number of patterns in the index
+func (idx *Index) Len() int {
+	return idx.size
+}
+
+// Insert adds the pattern to the index. It returns false if the pattern was already present.
+func (idx *Index) Insert(pattern CRN) bool {
+	n := idx.root
+	for _, s := range indexSegments(pattern) {
+		if s == "" {
+			if n.wildcard == nil {
+				n.wildcard = &indexNode{}
+			}
+			n = n.wildcard
+			continue
+		}
+		if n.children == nil {
+			n.children = make(map[string]*indexNode)
+		}
+		child, ok := n.children[s]
+		if !ok {
+			child = &indexNode{}
+			n.children[s] = child
+		}
+		n = child
+	}
+
+	if n.pattern != nil {
+		return false
+	}
+	n.pattern = &pattern
+	idx.size++
+	return true
+}
+
+// Remove deletes the pattern from the index. It returns false if the pattern was not present.
+func (idx *Index) Remove(pattern CRN) bool {
+	segments := indexSegments(pattern)
+	path := make([]*indexNode, 0, len(segments)+1)
+
+	n := idx.root
+	path = append(path, n)
+	for _, s := range segments {
+		if s == "" {
+			n = n.wildcard
+		} else {
+			n = n.children[s]
+		}
+		if n == nil {
+			return false
+		}
+		path = append(path, n)
+	}
+	if n.pattern == nil {
+		return false
+	}
+	n.pattern = nil
+	idx.size--
+
+	// prune the nodes which no longer lead to a pattern
+	for i := len(segments) - 1; i >= 0; i-- {
+		child := path[i+1]
+		if child.pattern != nil || child.wildcard != nil || len(child.children) > 0 {
+			break
+		}
+		if segments[i] == "" {
+			path[i].wildcard = nil
+		} else {
+			delete(path[i].children, segments[i])
+		}
+	}
+	return true
+}
+
+// Contains reports whether the pattern itself is in the index
+func (idx *Index) Contains(pattern CRN) bool {
+	n := idx.root
+	for _, s := range indexSegments(pattern) {
+		if s == "" {
+			n = n.wildcard
+		} else {
+			n = n.children[s]
+		}
+		if n == nil {
+			return false
+		}
+	}
+	return n.pattern != nil
+}
+
+// Match returns all patterns in the index that the CRN matches
+func (idx *Index) Match(c CRN) []CRN {
+	var ret []CRN
+	idx.walk(idx.root, indexSegments(c), func(p CRN) bool {
+		ret = append(ret, p)
+		return true
+	})
+	return ret
+}
+
+// MatchesAny reports whether the CRN matches at least one pattern in the index
+func (idx *Index) MatchesAny(c CRN) bool {
+	found := false
+	idx.walk(idx.root, indexSegments(c), func(CRN) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// walk calls fn for each pattern reachable by the segments until fn returns false
+func (idx *Index) walk(n *indexNode, segments []string, fn func(CRN) bool) bool {
+	if len(segments) == 0 {
+		if n.pattern != nil {
+			return fn(*n.pattern)
+		}
+		return true
+	}
+
+	if s := segments[0]; s != "" {
+		if child, ok := n.children[s]; ok {
+			if !idx.walk(child, segments[1:], fn) {
+				return false
+			}
+		}
+	}
+	if n.wildcard != nil {
+		return idx.walk(n.wildcard, segments[1:], fn)
+	}
+	return true
+}
+
+// Patterns returns all patterns in the index
+func (idx *Index) Patterns() []CRN {
+	ret := make([]CRN, 0, idx.size)
+	var collect func(n *indexNode)
+	collect = func(n *indexNode) {
+		if n.pattern != nil {
+			ret = append(ret, *n.pattern)
+		}
+		for _, child := range n.children {
+			collect(child)
+		}
+		if n.wildcard != nil {
+			collect(n.wildcard)
+		}
+	}
+	collect(idx.root)
+	return ret
+}
@@ -0,0 +1,115 @@
+package crn
+
+import (
+	"fmt"
+	"testing"
+)
+
+func mustParse(s string) CRN {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (suite *CRNTestSuite) TestIndexInsertRemoveContains() {
+	idx := NewIndex()
+	service := mustParse("crn:v1:bluemix:public:cloud-object-storage:::::")
+	instance := mustParse("crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:instance-guid::")
+
+	suite.True(idx.Insert(service))
+	suite.False(idx.Insert(service))
+	suite.True(idx.Insert(instance))
+	suite.Equal(2, idx.Len())
+
+	suite.True(idx.Contains(service))
+	suite.True(idx.Contains(instance))
+	suite.False(idx.Contains(mustParse("crn:v1:bluemix:public:iam:::::")))
+	// a CRN matching a pattern is not contained unless inserted itself
+	suite.False(idx.Contains(instance.Child("bucket", "my-bucket")))
+
+	suite.True(idx.Remove(instance))
+	suite.False(idx.Remove(instance))
+	suite.False(idx.Contains(instance))
+	suite.True(idx.Contains(service))
+	suite.Equal(1, idx.Len())
+	suite.Equal([]CRN{service}, idx.Patterns())
+
+	suite.True(idx.Remove(service))
+	suite.Equal(0, idx.Len())
+	suite.Nil(idx.root.wildcard)
+	suite.Empty(idx.root.children)
+}
+
+func (suite *CRNTestSuite) TestIndexMatch() {
+	patterns := []CRN{
+		mustParse("crn:v1:bluemix:public:cloud-object-storage:::::"),
+		mustParse("crn:v1:bluemix:public:::a/account-guid:::"),
+		mustParse("crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:instance-guid::"),
+		mustParse("crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:instance-guid:bucket:other"),
+		mustParse("crn:v1:bluemix:public:iam:::::"),
+		mustParse("crn:v1:bluemix:dedicated::::::"),
+	}
+	idx := NewIndex(patterns...)
+
+	c := mustParse("crn:v1:bluemix:public:cloud-object-storage:global:a/account-guid:instance-guid:bucket:my-bucket")
+	matched := idx.Match(c)
+	suite.ElementsMatch(patterns[:3], matched)
+	suite.True(idx.MatchesAny(c))
+
+	// the index agrees with CRN.Matches
+	for _, p := range patterns {
+		found := false
+		for _, m := range matched {
+			if m == p {
+				found = true
+			}
+		}
+		suite.Equal(c.Matches(p), found, p.String())
+	}
+
+	suite.False(idx.MatchesAny(mustParse("crn:v1:bluemix:local:kms:us-south:a/other:instance::")))
+	suite.Empty(idx.Match(mustParse("crn:v1:bluemix:local:kms:us-south:a/other:instance::")))
+}
+
+func benchmarkPatterns(n int) ([]CRN, []CRN) {
+	patterns := make([]CRN, 0, n)
+	resources := make([]CRN, 0, n)
+	for i := 0; i < n; i++ {
+		patterns = append(patterns, mustParse(fmt.Sprintf("crn:v1:bluemix:public:service-%d::a/account-%d:::", i%50, i)))
+		resources = append(resources, mustParse(fmt.Sprintf("crn:v1:bluemix:public:service-%d:us-south:a/account-%d:instance-%d:bucket:b-%d", i%50, (i*7)%n, i, i)))
+	}
+	return patterns, resources
+}
+
+func BenchmarkIndexMatch(b *testing.B) {
+	patterns, resources := benchmarkPatterns(5000)
+	idx := NewIndex(patterns...)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Match(resources[i%len(resources)])
+	}
+}
+
+func BenchmarkLinearMatch(b *testing.B) {
+	patterns, resources := benchmarkPatterns(5000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c := resources[i%len(resources)]
+		for _, p := range patterns {
+			c.Matches(p)
+		}
+	}
+}
+
+func BenchmarkIndexInsert(b *testing.B) {
+	patterns, _ := benchmarkPatterns(5000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewIndex(patterns...)
+	}
+}
@@ -0,0 +1,58 @@
+package crn
+
+// Matches reports whether the CRN matches the pattern. An empty segment of the pattern is a
+// wildcard matching any value, all other segments must be equal. For example the pattern
+// 'crn:v1:bluemix:public:cloud-object-storage:::::' matches every CRN of the service and
+// 'crn:v1:bluemix:public:::a/:::' matches every CRN scoped to an account.
+func (c CRN) Matches(pattern CRN) bool {
+	return matchSegment(pattern.Scheme, c.Scheme) &&
+		matchSegment(pattern.Version, c.Version) &&
+		matchSegment(pattern.CName, c.CName) &&
+		matchSegment(pattern.CType, c.CType) &&
+		matchSegment(pattern.ServiceName, c.ServiceName) &&
+		matchSegment(pattern.Region, c.Region) &&
+		matchSegment(pattern.ScopeType, c.ScopeType) &&
+		matchSegment(pattern.Scope, c.Scope) &&
+		matchSegment(pattern.ServiceInstance, c.ServiceInstance) &&
+		matchSegment(pattern.ResourceType, c.ResourceType) &&
+		matchSegment(pattern.Resource, c.Resource)
+}
+
+// MatchesString parses the pattern and reports whether the CRN matches it
+func (c CRN) MatchesString(pattern string) (bool, error) {
+	p, err := Parse(pattern)
+	if err != nil {
+		return false, err
+	}
+	return c.Matches(p), nil
+}
+
+func matchSegment(pattern, value string) bool {
+	return pattern == "" || pattern == value
+}
+
+// IsResource reports whether the CRN identifies a resource within a service instance
+func (c CRN) IsResource() bool {
+	return c.ResourceType != "" || c.Resource != ""
+}
+
+// Parent returns the CRN one level up in the hierarchy: the service instance of a resource,
+// or the service of a service instance. It returns false if the CRN has no parent.
+func (c CRN) Parent() (CRN, bool) {
+	switch {
+	case c.IsResource():
+		c.ResourceType, c.Resource = "", ""
+		return c, true
+	case c.ServiceInstance != "":
+		c.ServiceInstance = ""
+		return c, true
+	}
+	return c, false
+}
+
+// Child returns the CRN of a resource of the given type within the service instance
+func (c CRN) Child(resourceType string, resource string) CRN {
+	c.ResourceType = resourceType
+	c.Resource = resource
+	return c
+}
@@ -18,6 +18,11 @@ const (
 	Enterprise         Service = "enterprise"
 	ResourceController Service = "resource-controller"
 	ResourceCatalog    Service = "global-catalog"
+
+	// regional services
+	VPC                Service = "vpc"
+	CodeEngine         Service = "code-engine"
+	CloudObjectStorage Service = "cloud-object-storage"
 )
 
 func (s Service) String() string {
@@ -67,19 +72,54 @@ var endpointsMapping = map[Service]models.Endpoints{
 	},
 }
 
+// regionalServices have a public endpoint per region
+var regionalServices = []ServiceDefinition{
+	{
+		Name:     VPC,
+		Regional: true,
+		Endpoints: models.Endpoints{
+			PublicEndpoint:     "https://<region>.iaas.<cloud_domain>",
+			PrivateEndpoint:    "https://<region>.private.iaas.<cloud_domain>",
+			PrivateVPCEndpoint: "https://<region>.private.iaas.<cloud_domain>",
+		},
+	},
+	{
+		Name:     CodeEngine,
+		Regional: true,
+		Endpoints: models.Endpoints{
+			PublicEndpoint:     "https://api.<region>.codeengine.<cloud_domain>",
+			PrivateEndpoint:    "https://api.private.<region>.codeengine.<cloud_domain>",
+			PrivateVPCEndpoint: "https://api.private.<region>.codeengine.<cloud_domain>",
+		},
+	},
+	{
+		Name:     CloudObjectStorage,
+		Regional: true,
+		Endpoints: models.Endpoints{
+			PublicEndpoint:     "https://s3.<region>.cloud-object-storage.appdomain.cloud",
+			PrivateEndpoint:    "https://s3.private.<region>.cloud-object-storage.appdomain.cloud",
+			PrivateVPCEndpoint: "https://s3.direct.<region>.cloud-object-storage.appdomain.cloud",
+		},
+	},
+}
+
+// Endpoint returns the endpoint of a service from the URL templates in the default registry
 func Endpoint(svc Service, cloudDomain, region string, private, isVPC bool) (string, error) {
-	var endpoint string
-	if endpoints, found := endpointsMapping[svc]; found {
-		if private {
-			if isVPC {
-				endpoint = endpoints.PrivateVPCEndpoint
-			} else {
-			    endpoint = endpoints.PrivateEndpoint
-			}
-		} else {
-			endpoint = endpoints.PublicEndpoint
+	return defaultRegistry.Endpoint(svc, cloudDomain, region, private, isVPC)
+}
+
+func selectTemplate(endpoints models.Endpoints, private, isVPC bool) string {
+	if private {
+		if isVPC {
+			return endpoints.PrivateVPCEndpoint
 		}
+		return endpoints.PrivateEndpoint
 	}
+	return endpoints.PublicEndpoint
+}
+
+func endpointFromTemplates(svc Service, endpoints models.Endpoints, cloudDomain, region string, private, isVPC bool) (string, error) {
+	endpoint := selectTemplate(endpoints, private, isVPC)
 	if endpoint == "" {
 		return "", fmt.Errorf("the endpoint of service '%s' was unknown", svc)
 	}
@@ -2,9 +2,12 @@ package endpoints_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	. "github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/endpoints"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/models"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -68,3 +71,138 @@ func TestEndpointPrivateNoRegion(t *testing.T) {
 	_, err := Endpoint(AccountManagement, "cloud.ibm.com", "", true, false)
 	assert.Error(t, err, "an error is expected")
 }
+
+func TestRegisterService(t *testing.T) {
+	svc := Service("schematics")
+	err := Register(svc, models.Endpoints{
+		PublicEndpoint:  "https://schematics.<cloud_domain>",
+		PrivateEndpoint: "https://private-<region>.schematics.<cloud_domain>",
+	})
+	assert.NoError(t, err)
+
+	actual, err := Endpoint(svc, "cloud.ibm.com", "us-south", true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://private-us-south.schematics.cloud.ibm.com", actual)
+
+	_, err = Endpoint(svc, "cloud.ibm.com", "us-south", true, true)
+	assert.Error(t, err, "an error is expected")
+
+	assert.Error(t, Register(Service(""), models.Endpoints{PublicEndpoint: "https://example.com"}))
+	assert.Error(t, Register(Service("empty"), models.Endpoints{}))
+}
+
+func TestRegistryResolve(t *testing.T) {
+	overridesFile := filepath.Join(t.TempDir(), "endpoints.json")
+	registry := NewRegistry(overridesFile)
+	assert.NoError(t, registry.Register(Service("key-protect"), models.Endpoints{PublicEndpoint: "https://<region>.kms.<cloud_domain>"}))
+	assert.NoError(t, registry.Register(Service("iam-identity"), models.Endpoints{PublicEndpoint: "https://iam.<cloud_domain>"}))
+	assert.NoError(t, registry.Register(Service("billing"), models.Endpoints{PublicEndpoint: "https://billing.<cloud_domain>"}))
+
+	endpoint, source, err := registry.Resolve(Service("key-protect"), "cloud.ibm.com", "us-south", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://us-south.kms.cloud.ibm.com", endpoint)
+	assert.Equal(t, SourceRegistered, source)
+
+	// overrides file
+	assert.NoError(t, os.WriteFile(overridesFile, []byte(`{"key-protect": "http://localhost:8080"}`), 0600))
+	endpoint, source, err = registry.Resolve(Service("key-protect"), "cloud.ibm.com", "us-south", true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", endpoint)
+	assert.Equal(t, SourceConfigFile, source)
+
+	// environment variable takes precedence
+	assert.Equal(t, "IBMCLOUD_ENDPOINT_KEY_PROTECT", EnvName(Service("key-protect")))
+	t.Setenv("IBMCLOUD_ENDPOINT_KEY_PROTECT", "http://localhost:9090")
+	endpoint, source, err = registry.Resolve(Service("key-protect"), "cloud.ibm.com", "us-south", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:9090", endpoint)
+	assert.Equal(t, SourceEnv, source)
+
+	list := registry.List("cloud.ibm.com", "", false, false)
+	assert.Len(t, list, 3)
+	assert.Equal(t, ResolvedEndpoint{Service: "billing", Endpoint: "https://billing.cloud.ibm.com", Source: SourceRegistered}, list[0])
+	assert.Equal(t, ResolvedEndpoint{Service: "iam-identity", Endpoint: "https://iam.cloud.ibm.com", Source: SourceRegistered}, list[1])
+	assert.Equal(t, ResolvedEndpoint{Service: "key-protect", Endpoint: "http://localhost:9090", Source: SourceEnv}, list[2])
+
+	// malformed overrides file
+	assert.NoError(t, os.WriteFile(overridesFile, []byte(`not json`), 0600))
+	_, _, err = registry.Resolve(Service("billing"), "cloud.ibm.com", "", false, false)
+	assert.Error(t, err, "an error is expected")
+}
+
+func TestListBuiltinServices(t *testing.T) {
+	for _, resolved := range List("cloud.ibm.com", "us-south", false, false) {
+		if resolved.Service == ResourceController {
+			assert.Equal(t, "https://resource-controller.cloud.ibm.com", resolved.Endpoint)
+			assert.Equal(t, SourceBuiltin, resolved.Source)
+			return
+		}
+	}
+	assert.Fail(t, "resource-controller is not listed")
+}
+
+func TestEndpointRegionalServices(t *testing.T) {
+	actual, err := Endpoint(VPC, "cloud.ibm.com", "eu-de", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://eu-de.iaas.cloud.ibm.com", actual)
+
+	actual, err = Endpoint(CodeEngine, "cloud.ibm.com", "jp-tok", true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.private.jp-tok.codeengine.cloud.ibm.com", actual)
+
+	actual, err = Endpoint(CloudObjectStorage, "cloud.ibm.com", "us-south", true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://s3.direct.us-south.cloud-object-storage.appdomain.cloud", actual)
+
+	_, err = Endpoint(VPC, "cloud.ibm.com", "", false, false)
+	assert.Error(t, err, "an error is expected")
+
+	_, err = Endpoint(VPC, "cloud.ibm.com", "not-a-region", false, false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "'not-a-region' is not a valid region")
+	}
+	_, err = Endpoint(CloudObjectStorage, "cloud.ibm.com", "not-a-region", true, true)
+	assert.Error(t, err)
+
+	// the regions of the services resolved before the registry are not validated
+	actual, err = Endpoint(AccountManagement, "cloud.ibm.com", "mars-north", true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://private.mars-north.accounts.cloud.ibm.com", actual)
+
+	assert.True(t, DefaultRegistry().IsRegional(VPC))
+	assert.False(t, DefaultRegistry().IsRegional(ResourceController))
+}
+
+func TestRegistryRegionAvailability(t *testing.T) {
+	registry := NewRegistry("")
+	assert.NoError(t, registry.RegisterService(ServiceDefinition{
+		Name:      Service("satellite-link"),
+		Regional:  true,
+		Regions:   []string{"us-east", "eu-gb"},
+		Endpoints: models.Endpoints{PublicEndpoint: "https://<region>.link.<cloud_domain>"},
+	}))
+
+	actual, err := registry.Endpoint(Service("satellite-link"), "cloud.ibm.com", "eu-gb", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://eu-gb.link.cloud.ibm.com", actual)
+
+	_, err = registry.Endpoint(Service("satellite-link"), "cloud.ibm.com", "us-south", false, false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not available in region 'us-south'")
+	}
+
+	_, err = registry.Endpoint(Service("satellite-link"), "cloud.ibm.com", "mars-north", false, false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "'mars-north' is not a valid region")
+	}
+
+	registry.AddRegion("in-che")
+	assert.Contains(t, registry.Regions(), "in-che")
+
+	// a regional service needs a region-scoped public endpoint
+	assert.Error(t, registry.RegisterService(ServiceDefinition{
+		Name:      Service("global-only"),
+		Regional:  true,
+		Endpoints: models.Endpoints{PublicEndpoint: "https://global.<cloud_domain>"},
+	}))
+}
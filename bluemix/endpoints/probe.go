@@ -0,0 +1,241 @@
+package endpoints
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultProbeTimeout is the time allowed to probe a single endpoint
+const DefaultProbeTimeout = 5 * time.Second
+
+// Mode is the network mode used to access a service
+type Mode string
+
+func (m Mode) String() string {
+	return string(m)
+}
+
+const (
+	ModePublic     Mode = "public"
+	ModePrivate    Mode = "private"
+	ModePrivateVPC Mode = "private-vpc"
+)
+
+// PrivateEndpointEnabled reports whether the mode requires the private endpoint setting
+func (m Mode) PrivateEndpointEnabled() bool {
+	return m == ModePrivate || m == ModePrivateVPC
+}
+
+// AccessFromVPC reports whether the mode requires the VPC access setting
+func (m Mode) AccessFromVPC() bool {
+	return m == ModePrivateVPC
+}
+
+// Resolver looks up the addresses of a host. *net.Resolver implements it.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// ProbeResult is the outcome of probing the endpoint of a service in one mode
+type ProbeResult struct {
+	Mode Mode
+	URL  string
+	// Reachable is true if the endpoint answered the HEAD request, whatever the status code
+	Reachable  bool
+	StatusCode int
+	// Latency is the total time spent on the endpoint, DNS, TLS and Request are its phases
+	Latency time.Duration
+	DNS     time.Duration
+	TLS     time.Duration
+	Request time.Duration
+	// Err is the reason the endpoint is unreachable
+	Err error
+}
+
+// ProbeReport is the outcome of probing all endpoints of a service
+type ProbeReport struct {
+	Service Service
+	// Results are in the order public, private, private VPC. Modes without endpoint are omitted.
+	Results []ProbeResult
+	// Recommended is the mode to use, empty if no endpoint is reachable
+	Recommended Mode
+}
+
+// Result returns the result of the given mode
+func (r ProbeReport) Result(mode Mode) (ProbeResult, bool) {
+	for _, res := range r.Results {
+		if res.Mode == mode {
+			return res, true
+		}
+	}
+	return ProbeResult{}, false
+}
+
+// Prober checks which endpoints of a service are reachable from the current network
+type Prober struct {
+	Registry *Registry
+	// Resolver defaults to net.DefaultResolver
+	Resolver Resolver
+	// Timeout is the time allowed to probe each endpoint, defaults to DefaultProbeTimeout
+	Timeout   time.Duration
+	TLSConfig *tls.Config
+}
+
+// NewProber returns a prober for the services of the default registry
+func NewProber() *Prober {
+	return &Prober{
+		Registry: defaultRegistry,
+		Resolver: net.DefaultResolver,
+		Timeout:  DefaultProbeTimeout,
+	}
+}
+
+// Probe probes the endpoints of a service from the default registry
+func Probe(ctx context.Context, svc Service, cloudDomain, region string) (ProbeReport, error) {
+	return NewProber().Probe(ctx, svc, cloudDomain, region)
+}
+
+// Probe concurrently resolves, connects to and sends a HEAD request to the public, private
+// and private VPC endpoints of the service, and recommends the mode to use. Private modes are
+// preferred over the public one when reachable, the private VPC endpoint being only reachable
+// from a VPC.
+func (p *Prober) Probe(ctx context.Context, svc Service, cloudDomain, region string) (ProbeReport, error) {
+	registry := p.Registry
+	if registry == nil {
+		registry = defaultRegistry
+	}
+	if _, found := registry.Definition(svc); !found {
+		return ProbeReport{}, fmt.Errorf("the endpoint of service '%s' was unknown", svc)
+	}
+
+	var targets []ProbeResult
+	for _, mode := range []Mode{ModePublic, ModePrivate, ModePrivateVPC} {
+		endpoint, err := registry.Endpoint(svc, cloudDomain, region, mode.PrivateEndpointEnabled(), mode.AccessFromVPC())
+		if err != nil {
+			continue
+		}
+		targets = append(targets, ProbeResult{Mode: mode, URL: endpoint})
+	}
+	if len(targets) == 0 {
+		return ProbeReport{}, fmt.Errorf("no endpoint of service '%s' is available in region '%s'", svc, region)
+	}
+
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		go func(res *ProbeResult) {
+			defer wg.Done()
+			p.probe(ctx, res)
+		}(&targets[i])
+	}
+	wg.Wait()
+
+	report := ProbeReport{Service: svc, Results: targets}
+	for _, mode := range []Mode{ModePrivateVPC, ModePrivate, ModePublic} {
+		if res, found := report.Result(mode); found && res.Reachable {
+			report.Recommended = mode
+			break
+		}
+	}
+	return report, nil
+}
+
+func (p *Prober) probe(ctx context.Context, res *ProbeResult) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() { res.Latency = time.Since(start) }()
+
+	u, err := url.Parse(res.URL)
+	if err != nil {
+		res.Err = err
+		return
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	addrs, err := p.lookup(ctx, u.Hostname())
+	res.DNS = time.Since(start)
+	if err != nil {
+		res.Err = err
+		return
+	}
+
+	var tlsStart time.Time
+	trace := &httptrace.ClientTrace{
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			if !tlsStart.IsZero() {
+				res.TLS = time.Since(tlsStart)
+			}
+		},
+	}
+
+	dialer := &net.Dialer{}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var lastErr error
+			for _, addr := range addrs {
+				conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+				if err == nil {
+					return conn, nil
+				}
+				lastErr = err
+			}
+			return nil, lastErr
+		},
+		TLSClientConfig:   p.TLSConfig,
+		DisableKeepAlives: true,
+	}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodHead, res.URL, nil)
+	if err != nil {
+		res.Err = err
+		return
+	}
+
+	reqStart := time.Now()
+	resp, err := transport.RoundTrip(req)
+	res.Request = time.Since(reqStart)
+	if err != nil {
+		res.Err = err
+		return
+	}
+	resp.Body.Close()
+
+	res.Reachable = true
+	res.StatusCode = resp.StatusCode
+}
+
+func (p *Prober) lookup(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+	resolver := p.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	addrs, err := resolver.LookupHost(ctx, host)
+	if err == nil && len(addrs) == 0 {
+		err = fmt.Errorf("no address found for host '%s'", host)
+	}
+	return addrs, err
+}
@@ -0,0 +1,105 @@
+package endpoints_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/endpoints"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := r[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func newProber(t *testing.T, resolver fakeResolver) (*Prober, string) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	registry := NewRegistry("")
+	assert.NoError(t, registry.Register(Service("probed"), models.Endpoints{
+		PublicEndpoint:     "https://public.<cloud_domain>",
+		PrivateEndpoint:    "https://private.<region>.<cloud_domain>",
+		PrivateVPCEndpoint: "https://vpc.<region>.<cloud_domain>",
+	}))
+
+	return &Prober{
+		Registry:  registry,
+		Resolver:  resolver,
+		Timeout:   2 * time.Second,
+		TLSConfig: &tls.Config{InsecureSkipVerify: true},
+	}, fmt.Sprintf("example.test:%s", port)
+}
+
+func TestProbeRecommendsPrivate(t *testing.T) {
+	prober, cloudDomain := newProber(t, fakeResolver{
+		"public.example.test":           {"127.0.0.1"},
+		"private.us-south.example.test": {"127.0.0.1"},
+	})
+
+	report, err := prober.Probe(context.Background(), Service("probed"), cloudDomain, "us-south")
+	assert.NoError(t, err)
+	assert.Len(t, report.Results, 3)
+	assert.Equal(t, ModePrivate, report.Recommended)
+	assert.True(t, report.Recommended.PrivateEndpointEnabled())
+	assert.False(t, report.Recommended.AccessFromVPC())
+
+	public, _ := report.Result(ModePublic)
+	assert.True(t, public.Reachable)
+	assert.Equal(t, http.StatusNotFound, public.StatusCode)
+	assert.NoError(t, public.Err)
+	assert.True(t, public.TLS > 0)
+	assert.True(t, public.Latency >= public.Request)
+
+	vpc, _ := report.Result(ModePrivateVPC)
+	assert.False(t, vpc.Reachable)
+	assert.Error(t, vpc.Err)
+}
+
+func TestProbeRecommendsPrivateVPC(t *testing.T) {
+	prober, cloudDomain := newProber(t, fakeResolver{
+		"public.example.test":        {"127.0.0.1"},
+		"private.eu-de.example.test": {"127.0.0.1"},
+		"vpc.eu-de.example.test":     {"127.0.0.1"},
+	})
+
+	report, err := prober.Probe(context.Background(), Service("probed"), cloudDomain, "eu-de")
+	assert.NoError(t, err)
+	assert.Equal(t, ModePrivateVPC, report.Recommended)
+	assert.True(t, report.Recommended.AccessFromVPC())
+}
+
+func TestProbeUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	prober, _ := newProber(t, fakeResolver{"public.example.test": {"127.0.0.1"}})
+	report, err := prober.Probe(context.Background(), Service("probed"), "example.test:"+port, "us-south")
+	assert.NoError(t, err)
+	assert.Equal(t, Mode(""), report.Recommended)
+	for _, res := range report.Results {
+		assert.False(t, res.Reachable, res.Mode.String())
+		assert.Error(t, res.Err, res.Mode.String())
+	}
+
+	_, err = prober.Probe(context.Background(), Service("unknown"), "example.test", "us-south")
+	assert.Error(t, err)
+}
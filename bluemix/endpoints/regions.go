@@ -0,0 +1,94 @@
+package endpoints
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/models"
+)
+
+// KnownRegions are the IBM Cloud regions that region-scoped endpoints are validated against
+var KnownRegions = []string{
+	"au-syd",
+	"br-sao",
+	"ca-mon",
+	"ca-tor",
+	"eu-de",
+	"eu-es",
+	"eu-gb",
+	"jp-osa",
+	"jp-tok",
+	"us-east",
+	"us-south",
+}
+
+// ServiceDefinition describes the endpoints of a service
+type ServiceDefinition struct {
+	Name      Service
+	Endpoints models.Endpoints
+	// Regional services have a public endpoint per region, e.g. 'https://<region>.iaas.<cloud_domain>'.
+	// Global services have a single public endpoint and may only have regional private endpoints.
+	Regional bool
+	// Regions restricts the service to the given regions. Empty means the service is available in all known regions.
+	Regions []string
+}
+
+// IsGlobal reports whether the service has a single public endpoint for all regions
+func (d ServiceDefinition) IsGlobal() bool {
+	return !d.Regional
+}
+
+// AvailableIn reports whether the service is available in the region
+func (d ServiceDefinition) AvailableIn(region string) bool {
+	if len(d.Regions) == 0 {
+		return true
+	}
+	for _, r := range d.Regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Registry) validateRegion(def ServiceDefinition, region string) error {
+	r.mu.RLock()
+	_, known := r.regions[region]
+	r.mu.RUnlock()
+
+	if !known {
+		return fmt.Errorf("'%s' is not a valid region. Valid regions are: %s", region, strings.Join(r.Regions(), ", "))
+	}
+	if !def.AvailableIn(region) {
+		return fmt.Errorf("service '%s' is not available in region '%s'. Available regions are: %s", def.Name, region, strings.Join(def.Regions, ", "))
+	}
+	return nil
+}
+
+// AddRegion adds regions to the list of valid regions of the registry
+func (r *Registry) AddRegion(regions ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, region := range regions {
+		r.regions[region] = struct{}{}
+	}
+}
+
+// Regions returns the valid regions of the registry in alphabetical order
+func (r *Registry) Regions() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ret := make([]string, 0, len(r.regions))
+	for region := range r.regions {
+		ret = append(ret, region)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// IsRegional reports whether the service has a public endpoint per region
+func (r *Registry) IsRegional(svc Service) bool {
+	def, found := r.Definition(svc)
+	return found && def.Regional
+}
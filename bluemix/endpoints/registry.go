@@ -0,0 +1,279 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/configuration/config_helpers"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/models"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/common/file_helpers"
+)
+
+// EnvEndpointPrefix is the prefix of the environment variables overriding the endpoint of a
+// service, e.g. `IBMCLOUD_ENDPOINT_RESOURCE_CONTROLLER` for the 'resource-controller' service
+const EnvEndpointPrefix = "IBMCLOUD_ENDPOINT_"
+
+// Source is where the effective endpoint of a service comes from
+type Source string
+
+func (s Source) String() string {
+	return string(s)
+}
+
+const (
+	SourceEnv        Source = "env"
+	SourceConfigFile Source = "config"
+	SourceBuiltin    Source = "builtin"
+	SourceRegistered Source = "registered"
+)
+
+// ResolvedEndpoint is the effective endpoint of a service
+type ResolvedEndpoint struct {
+	Service  Service
+	Endpoint string
+	Source   Source
+	// Err is set when the endpoint could not be resolved
+	Err error
+}
+
+// Registry holds the URL templates of services and resolves their endpoints taking
+// user overrides into account. Templates may contain the '<cloud_domain>' and '<region>' placeholders.
+type Registry struct {
+	mu            sync.RWMutex
+	services      map[Service]ServiceDefinition
+	builtin       map[Service]bool
+	anyRegion     map[Service]bool // services whose regions are not validated
+	regions       map[string]struct{}
+	overridesFile func() string
+}
+
+// NewRegistry returns a registry without services which validates regions against KnownRegions. Overrides are read from the given JSON file which maps
+// service names to endpoints, e.g. {"resource-controller": "http://localhost:8080"}. An empty path
+// disables file overrides.
+func NewRegistry(overridesFile string) *Registry {
+	r := &Registry{
+		services:      make(map[Service]ServiceDefinition),
+		builtin:       make(map[Service]bool),
+		anyRegion:     make(map[Service]bool),
+		regions:       make(map[string]struct{}),
+		overridesFile: func() string { return overridesFile },
+	}
+	r.AddRegion(KnownRegions...)
+	return r
+}
+
+var defaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry("")
+	// the config directory can change after the package is initialized
+	r.overridesFile = config_helpers.EndpointsOverridesFilePath
+	for svc, templates := range endpointsMapping {
+		r.services[svc] = ServiceDefinition{Name: svc, Endpoints: templates}
+		r.builtin[svc] = true
+		// the SDK resolved the endpoints of these services in any region before the registry
+		r.anyRegion[svc] = true
+	}
+	for _, def := range regionalServices {
+		r.services[def.Name] = def
+		r.builtin[def.Name] = true
+	}
+	return r
+}
+
+// DefaultRegistry returns the registry used by Endpoint and Resolve. It contains the services
+// known by the SDK and the services registered by the plugin.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register adds the URL templates of a service to the default registry
+func Register(svc Service, templates models.Endpoints) error {
+	return defaultRegistry.Register(svc, templates)
+}
+
+// RegisterService adds a service definition to the default registry
+func RegisterService(def ServiceDefinition) error {
+	return defaultRegistry.RegisterService(def)
+}
+
+// Resolve returns the effective endpoint of a service from the default registry
+func Resolve(svc Service, cloudDomain, region string, private, isVPC bool) (string, Source, error) {
+	return defaultRegistry.Resolve(svc, cloudDomain, region, private, isVPC)
+}
+
+// List returns the effective endpoints of all services in the default registry
+func List(cloudDomain, region string, private, isVPC bool) []ResolvedEndpoint {
+	return defaultRegistry.List(cloudDomain, region, private, isVPC)
+}
+
+// Register adds or replaces the URL templates of a service. The service is regional
+// if its public endpoint template contains the '<region>' placeholder.
+func (r *Registry) Register(svc Service, templates models.Endpoints) error {
+	return r.RegisterService(ServiceDefinition{
+		Name:      svc,
+		Endpoints: templates,
+		Regional:  strings.Contains(templates.PublicEndpoint, "<region>"),
+	})
+}
+
+// RegisterService adds or replaces a service definition
+func (r *Registry) RegisterService(def ServiceDefinition) error {
+	if def.Name == "" {
+		return fmt.Errorf("the service name is empty")
+	}
+	templates := def.Endpoints
+	if templates.PublicEndpoint == "" && templates.PrivateEndpoint == "" && templates.PrivateVPCEndpoint == "" {
+		return fmt.Errorf("no endpoint template is provided for service '%s'", def.Name)
+	}
+	if def.Regional && templates.PublicEndpoint != "" && !strings.Contains(templates.PublicEndpoint, "<region>") {
+		return fmt.Errorf("the public endpoint template of regional service '%s' must contain '<region>'", def.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.services[def.Name] = def
+	r.builtin[def.Name] = false
+	delete(r.anyRegion, def.Name)
+	return nil
+}
+
+// Templates returns the URL templates of a service
+func (r *Registry) Templates(svc Service) (models.Endpoints, bool) {
+	def, found := r.Definition(svc)
+	return def.Endpoints, found
+}
+
+// Definition returns the definition of a service
+func (r *Registry) Definition(svc Service) (ServiceDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def, found := r.services[svc]
+	return def, found
+}
+
+// Services returns the names of all services in the registry in alphabetical order
+func (r *Registry) Services() []Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ret := make([]Service, 0, len(r.services))
+	for svc := range r.services {
+		ret = append(ret, svc)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
+
+// Endpoint returns the endpoint of a service from its URL templates, ignoring user overrides.
+// If the template is region-scoped, the region must be a valid region where the service is
+// available. The regions of the services resolved by the SDK before the registry, such as
+// global-search, are not validated to keep their endpoints resolving in any region.
+func (r *Registry) Endpoint(svc Service, cloudDomain, region string, private, isVPC bool) (string, error) {
+	def, _ := r.Definition(svc)
+	endpoint, err := endpointFromTemplates(svc, def.Endpoints, cloudDomain, region, private, isVPC)
+	if err != nil {
+		return "", err
+	}
+	r.mu.RLock()
+	anyRegion := r.anyRegion[svc]
+	r.mu.RUnlock()
+	if !anyRegion && region != "" && strings.Contains(selectTemplate(def.Endpoints, private, isVPC), "<region>") {
+		if err := r.validateRegion(def, region); err != nil {
+			return "", err
+		}
+	}
+	return endpoint, nil
+}
+
+// Override returns the endpoint of a service set by the user through the environment
+// variable or the overrides file
+func (r *Registry) Override(svc Service) (string, Source, bool, error) {
+	overrides, err := r.loadOverrides()
+	if err != nil {
+		return "", "", false, err
+	}
+	endpoint, source, found := r.override(svc, overrides)
+	return endpoint, source, found, nil
+}
+
+func (r *Registry) override(svc Service, fileOverrides map[string]string) (string, Source, bool) {
+	if v := os.Getenv(EnvName(svc)); v != "" {
+		return v, SourceEnv, true
+	}
+	if v := fileOverrides[svc.String()]; v != "" {
+		return v, SourceConfigFile, true
+	}
+	return "", "", false
+}
+
+// Resolve returns the effective endpoint of a service and where it comes from. User overrides take
+// precedence over the URL templates. The environment variable takes precedence over the overrides file.
+func (r *Registry) Resolve(svc Service, cloudDomain, region string, private, isVPC bool) (string, Source, error) {
+	overrides, err := r.loadOverrides()
+	if err != nil {
+		return "", "", err
+	}
+	resolved := r.resolve(svc, overrides, cloudDomain, region, private, isVPC)
+	return resolved.Endpoint, resolved.Source, resolved.Err
+}
+
+// List returns the effective endpoints of all services in the registry
+func (r *Registry) List(cloudDomain, region string, private, isVPC bool) []ResolvedEndpoint {
+	overrides, err := r.loadOverrides()
+	var ret []ResolvedEndpoint
+	for _, svc := range r.Services() {
+		if err != nil {
+			ret = append(ret, ResolvedEndpoint{Service: svc, Err: err})
+			continue
+		}
+		ret = append(ret, r.resolve(svc, overrides, cloudDomain, region, private, isVPC))
+	}
+	return ret
+}
+
+func (r *Registry) resolve(svc Service, overrides map[string]string, cloudDomain, region string, private, isVPC bool) ResolvedEndpoint {
+	if endpoint, source, found := r.override(svc, overrides); found {
+		return ResolvedEndpoint{Service: svc, Endpoint: endpoint, Source: source}
+	}
+
+	r.mu.RLock()
+	source := SourceRegistered
+	if r.builtin[svc] {
+		source = SourceBuiltin
+	}
+	r.mu.RUnlock()
+
+	endpoint, err := r.Endpoint(svc, cloudDomain, region, private, isVPC)
+	if err != nil {
+		return ResolvedEndpoint{Service: svc, Err: err}
+	}
+	return ResolvedEndpoint{Service: svc, Endpoint: endpoint, Source: source}
+}
+
+// loadOverrides reads the overrides file. A missing file means no overrides.
+func (r *Registry) loadOverrides() (map[string]string, error) {
+	path := r.overridesFile()
+	if path == "" || !file_helpers.FileExists(path) {
+		return nil, nil
+	}
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides map[string]string
+	if err := json.Unmarshal(bytes, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid endpoint overrides file '%s': %v", path, err)
+	}
+	return overrides, nil
+}
+
+// EnvName returns the name of the environment variable overriding the endpoint of the service
+func EnvName(svc Service) string {
+	return EnvEndpointPrefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(svc.String()))
+}
@@ -31,11 +31,22 @@ var (
 	EnvConfigDir = newEnv("IBMCLOUD_CONFIG_HOME")
 	// EnvQuiet is the environment variable `IBMCLOUD_QUIET`
 	EnvQuiet = newEnv("IBMCLOUD_QUIET")
+	// EnvTraceHAR is the environment variable `IBMCLOUD_TRACE_HAR`, the path of the HAR file recording the HTTP exchanges
+	EnvTraceHAR = newEnv("IBMCLOUD_TRACE_HAR")
+	// EnvTraceCurl is the environment variable `IBMCLOUD_TRACE_CURL`, 'true' or 'token' to trace requests as curl commands
+	EnvTraceCurl = newEnv("IBMCLOUD_TRACE_CURL")
+	// EnvCACertFile is the environment variable `IBMCLOUD_CA_CERT_FILE`, a list of PEM files separated by the OS path list separator
+	EnvCACertFile = newEnv("IBMCLOUD_CA_CERT_FILE")
+	// EnvClientCertFile is the environment variable `IBMCLOUD_CLIENT_CERT_FILE`
+	EnvClientCertFile = newEnv("IBMCLOUD_CLIENT_CERT_FILE")
+	// EnvClientKeyFile is the environment variable `IBMCLOUD_CLIENT_KEY_FILE`
+	EnvClientKeyFile = newEnv("IBMCLOUD_CLIENT_KEY_FILE")
 
 	// for internal use
 	EnvCLIName         = newEnv("IBMCLOUD_CLI", "BLUEMIX_CLI")
 	EnvPluginNamespace = newEnv("IBMCLOUD_PLUGIN_NAMESPACE", "BLUEMIX_PLUGIN_NAMESPACE")
 	EnvMCP             = newEnv("IBMCLOUD_MCP_ENABLED")
+	EnvPluginHandshake = newEnv("IBMCLOUD_PLUGIN_HANDSHAKE")
 )
 
 // Env is an environment variable supported by IBM Cloud CLI for specific purpose
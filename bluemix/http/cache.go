@@ -0,0 +1,294 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/configuration/config_helpers"
+)
+
+// CacheStatusHeader is set on responses returned by CacheTransport. Its value is one of
+// CacheHit, CacheRevalidated or CacheMiss.
+const CacheStatusHeader = "X-Cache-Status"
+
+const (
+	// CacheHit is a fresh response served from the cache without contacting the server
+	CacheHit = "HIT"
+	// CacheRevalidated is a response served from the cache after the server answered 304 Not Modified
+	CacheRevalidated = "REVALIDATED"
+	// CacheMiss is a response from the server
+	CacheMiss = "MISS"
+)
+
+// CacheTransport is a RoundTripper caching the responses of GET requests on disk. A cached
+// response is served without contacting the server while it is fresh according to the
+// 'Cache-Control: max-age' directive, and is revalidated with 'If-None-Match' and
+// 'If-Modified-Since' once stale. Responses are stored per account and per credential
+// (Authorization header) so that they are never served to another session, and a response is
+// only served to requests with the same values of the headers listed in its 'Vary' header.
+//
+// Example:
+//   client := &gohttp.Client{
+//       Transport: http.NewCacheTransport(nil, config_helpers.HTTPCacheDir(), context.CurrentAccount().GUID),
+//   }
+type CacheTransport struct {
+	rt  http.RoundTripper
+	dir string
+
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	URL      string
+	StoredAt time.Time
+	// Vary maps the request headers listed in the Vary header of the response to the hash of their values
+	Vary map[string]string
+	// Response is the dumped response including its body
+	Response []byte
+}
+
+// NewCacheTransport creates a CacheTransport wrapping around the passed RoundTripper storing
+// responses of the given account under dir. If the passed RoundTripper is nil, HTTP DefaultTransport is used.
+func NewCacheTransport(rt http.RoundTripper, dir string, account string) *CacheTransport {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if account == "" {
+		account = "anonymous"
+	}
+	return &CacheTransport{
+		rt:  rt,
+		dir: filepath.Join(dir, hash(account)),
+		now: time.Now,
+	}
+}
+
+// NewDefaultCacheTransport creates a CacheTransport storing responses under the HTTP cache directory of the CLI
+func NewDefaultCacheTransport(rt http.RoundTripper, account string) *CacheTransport {
+	return NewCacheTransport(rt, config_helpers.HTTPCacheDir(), account)
+}
+
+// Clear removes all responses cached for the account
+func (t *CacheTransport) Clear() error {
+	return os.RemoveAll(t.dir)
+}
+
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !cacheableRequest(req) {
+		return t.rt.RoundTrip(req)
+	}
+
+	path := filepath.Join(t.dir, cacheKey(req)+".json")
+	entry, cached := t.load(path, req)
+	if cached != nil && isFresh(cached.Header, entry.StoredAt, t.now()) {
+		cached.Header.Set(CacheStatusHeader, CacheHit)
+		return cached, nil
+	}
+
+	outgoing := req
+	if cached != nil {
+		etag := cached.Header.Get("ETag")
+		lastModified := cached.Header.Get("Last-Modified")
+		if etag != "" || lastModified != "" {
+			// a RoundTripper must not modify the request
+			outgoing = req.Clone(req.Context())
+			if etag != "" {
+				outgoing.Header.Set("If-None-Match", etag)
+			}
+			if lastModified != "" {
+				outgoing.Header.Set("If-Modified-Since", lastModified)
+			}
+		}
+	}
+
+	resp, err := t.rt.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		for k, v := range resp.Header {
+			cached.Header[k] = v
+		}
+		_ = t.store(path, req, cached)
+		cached.Header.Set(CacheStatusHeader, CacheRevalidated)
+		return cached, nil
+	}
+
+	if cacheableResponse(resp) {
+		// the cache is best effort, the response is returned even if it can not be stored
+		_ = t.store(path, req, resp)
+	}
+	resp.Header.Set(CacheStatusHeader, CacheMiss)
+	return resp, nil
+}
+
+// cacheKey returns the key of the cached response of the request, which depends on the URL and
+// the credential of the request. The credential is hashed so that it is not stored on disk.
+func cacheKey(req *http.Request) string {
+	return hash(req.URL.String() + "\n" + hash(req.Header.Get("Authorization")))
+}
+
+// varyHeaders returns the names of the request headers listed in the Vary header of the response
+func varyHeaders(header http.Header) []string {
+	var names []string
+	for _, v := range header.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
+// varyValues returns the hash of the values of the request headers listed in the Vary header of the response
+func varyValues(header http.Header, req *http.Request) map[string]string {
+	var values map[string]string
+	for _, name := range varyHeaders(header) {
+		if values == nil {
+			values = make(map[string]string)
+		}
+		values[name] = hash(strings.Join(req.Header.Values(name), ","))
+	}
+	return values
+}
+
+func cacheableRequest(req *http.Request) bool {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return false
+	}
+	_, noStore := cacheControl(req.Header)["no-store"]
+	return !noStore
+}
+
+func cacheableResponse(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	cc := cacheControl(resp.Header)
+	if _, noStore := cc["no-store"]; noStore {
+		return false
+	}
+	// 'Vary: *' means that the response depends on more than the request headers
+	if slices.Contains(varyHeaders(resp.Header), "*") {
+		return false
+	}
+	if resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "" {
+		return true
+	}
+	return maxAge(cc) > 0
+}
+
+func isFresh(header http.Header, storedAt time.Time, now time.Time) bool {
+	cc := cacheControl(header)
+	if _, noCache := cc["no-cache"]; noCache {
+		return false
+	}
+	return now.Sub(storedAt) < maxAge(cc)
+}
+
+func maxAge(cc map[string]string) time.Duration {
+	seconds, err := strconv.Atoi(cc["max-age"])
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// cacheControl parses the directives of the Cache-Control header
+func cacheControl(header http.Header) map[string]string {
+	cc := make(map[string]string)
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(directive, "=")
+		cc[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return cc
+}
+
+func (t *CacheTransport) load(path string, req *http.Request) (cacheEntry, *http.Response) {
+	var entry cacheEntry
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return entry, nil
+	}
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != req.URL.String() {
+		return entry, nil
+	}
+	for name, value := range entry.Vary {
+		if hash(strings.Join(req.Header.Values(name), ",")) != value {
+			return entry, nil
+		}
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(entry.Response)), req)
+	if err != nil {
+		return entry, nil
+	}
+	return entry, resp
+}
+
+// store saves the response and replaces its body so that it can still be read by the caller
+func (t *CacheTransport) store(path string, req *http.Request, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	stored := *resp
+	stored.Header = resp.Header.Clone()
+	stored.Header.Del(CacheStatusHeader)
+	stored.Body = io.NopCloser(bytes.NewReader(body))
+	stored.ContentLength = int64(len(body))
+	stored.TransferEncoding = nil
+	dumped, err := httputil.DumpResponse(&stored, true)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cacheEntry{URL: req.URL.String(), StoredAt: t.now(), Vary: varyValues(resp.Header, req), Response: dumped})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return err
+	}
+	// write to a temporary file first so that concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(t.dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
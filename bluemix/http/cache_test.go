@@ -0,0 +1,190 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheTestServer struct {
+	*httptest.Server
+	requests []*http.Request
+	body     string
+	etag     string
+	cc       string
+	vary     string
+}
+
+func newCacheTestServer(t *testing.T) *cacheTestServer {
+	s := &cacheTestServer{body: "v1", etag: `"v1"`}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests = append(s.requests, r)
+		if s.cc != "" {
+			w.Header().Set("Cache-Control", s.cc)
+		}
+		if s.vary != "" {
+			w.Header().Set("Vary", s.vary)
+		}
+		if s.etag != "" {
+			w.Header().Set("ETag", s.etag)
+			if r.Header.Get("If-None-Match") == s.etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		fmt.Fprint(w, s.body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func get(t *testing.T, client *http.Client, url string) (string, string) {
+	resp, err := client.Get(url)
+	if !assert.NoError(t, err) {
+		return "", ""
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return string(body), resp.Header.Get(CacheStatusHeader)
+}
+
+func TestCacheTransportMaxAge(t *testing.T) {
+	server := newCacheTestServer(t)
+	server.cc = "max-age=60"
+
+	now := time.Now()
+	transport := NewCacheTransport(nil, t.TempDir(), "account-1")
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	body, status := get(t, client, server.URL)
+	assert.Equal(t, "v1", body)
+	assert.Equal(t, CacheMiss, status)
+
+	body, status = get(t, client, server.URL)
+	assert.Equal(t, "v1", body)
+	assert.Equal(t, CacheHit, status)
+	assert.Len(t, server.requests, 1)
+
+	// stale after max-age, revalidated with the ETag
+	now = now.Add(2 * time.Minute)
+	body, status = get(t, client, server.URL)
+	assert.Equal(t, "v1", body)
+	assert.Equal(t, CacheRevalidated, status)
+	if assert.Len(t, server.requests, 2) {
+		assert.Equal(t, `"v1"`, server.requests[1].Header.Get("If-None-Match"))
+	}
+
+	// fresh again after revalidation
+	body, status = get(t, client, server.URL)
+	assert.Equal(t, CacheHit, status)
+	assert.Len(t, server.requests, 2)
+}
+
+func TestCacheTransportRevalidation(t *testing.T) {
+	server := newCacheTestServer(t)
+	client := &http.Client{Transport: NewCacheTransport(nil, t.TempDir(), "account-1")}
+
+	_, status := get(t, client, server.URL)
+	assert.Equal(t, CacheMiss, status)
+
+	_, status = get(t, client, server.URL)
+	assert.Equal(t, CacheRevalidated, status)
+
+	server.body, server.etag = "v2", `"v2"`
+	body, status := get(t, client, server.URL)
+	assert.Equal(t, "v2", body)
+	assert.Equal(t, CacheMiss, status)
+	assert.Len(t, server.requests, 3)
+}
+
+func TestCacheTransportPerAccount(t *testing.T) {
+	server := newCacheTestServer(t)
+	server.cc = "max-age=60"
+	dir := t.TempDir()
+
+	_, status := get(t, &http.Client{Transport: NewCacheTransport(nil, dir, "account-1")}, server.URL)
+	assert.Equal(t, CacheMiss, status)
+	_, status = get(t, &http.Client{Transport: NewCacheTransport(nil, dir, "account-2")}, server.URL)
+	assert.Equal(t, CacheMiss, status)
+	_, status = get(t, &http.Client{Transport: NewCacheTransport(nil, dir, "account-1")}, server.URL)
+	assert.Equal(t, CacheHit, status)
+
+	transport := NewCacheTransport(nil, dir, "account-1")
+	assert.NoError(t, transport.Clear())
+	_, status = get(t, &http.Client{Transport: transport}, server.URL)
+	assert.Equal(t, CacheMiss, status)
+}
+
+func TestCacheTransportNotCacheable(t *testing.T) {
+	server := newCacheTestServer(t)
+	server.cc = "no-store"
+	client := &http.Client{Transport: NewCacheTransport(nil, t.TempDir(), "")}
+
+	get(t, client, server.URL)
+	_, status := get(t, client, server.URL)
+	assert.Equal(t, CacheMiss, status)
+
+	server.cc = "max-age=60"
+	resp, err := client.Post(server.URL, "text/plain", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, resp.Header.Get(CacheStatusHeader))
+	assert.Len(t, server.requests, 3)
+}
+
+func TestCacheTransportPerCredential(t *testing.T) {
+	server := newCacheTestServer(t)
+	server.cc = "max-age=60"
+	client := &http.Client{Transport: NewCacheTransport(nil, t.TempDir(), "account-1")}
+
+	getWithHeader := func(name, value string) string {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set(name, value)
+		resp, err := client.Do(req)
+		if !assert.NoError(t, err) {
+			return ""
+		}
+		resp.Body.Close()
+		return resp.Header.Get(CacheStatusHeader)
+	}
+
+	assert.Equal(t, CacheMiss, getWithHeader("Authorization", "Bearer token-1"))
+	assert.Equal(t, CacheHit, getWithHeader("Authorization", "Bearer token-1"))
+	assert.Equal(t, CacheMiss, getWithHeader("Authorization", "Bearer token-2"))
+	assert.Len(t, server.requests, 2)
+}
+
+func TestCacheTransportVary(t *testing.T) {
+	server := newCacheTestServer(t)
+	server.cc = "max-age=60"
+	server.vary = "Accept-Language"
+	client := &http.Client{Transport: NewCacheTransport(nil, t.TempDir(), "account-1")}
+
+	getWithLanguage := func(language string) string {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set("Accept-Language", language)
+		resp, err := client.Do(req)
+		if !assert.NoError(t, err) {
+			return ""
+		}
+		resp.Body.Close()
+		return resp.Header.Get(CacheStatusHeader)
+	}
+
+	assert.Equal(t, CacheMiss, getWithLanguage("en"))
+	assert.Equal(t, CacheHit, getWithLanguage("en"))
+	assert.Equal(t, CacheMiss, getWithLanguage("de"))
+
+	// the response depends on more than the request headers
+	server.vary = "*"
+	assert.Equal(t, CacheMiss, getWithLanguage("fr"))
+	assert.Equal(t, CacheMiss, getWithLanguage("fr"))
+	assert.Len(t, server.requests, 4)
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/common/rest"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin"
+)
+
+// RequestIDHeader is the header carrying the ID of a request
+const RequestIDHeader = "X-Request-Id"
+
+// ClientOptions identifies the plugin creating the client. The identity is added to the User-Agent.
+type ClientOptions struct {
+	// PluginName and PluginVersion are the name and version of the plugin from its metadata
+	PluginName    string
+	PluginVersion plugin.VersionType
+	// CLIVersion is the version of the CLI invoking the plugin, omitted from the User-Agent if empty
+	CLIVersion string
+	// Cache enables the on-disk cache of GET responses of the targeted account, see CacheTransport
+	Cache bool
+	// TLS are the TLS settings of the client. If nil, they are taken from the plugin context, see
+	// TLSOptionsFromContext.
+	TLS *TLSOptions
+}
+
+// TLSOptionsFromContext returns the TLS settings of the plugin context. The additional CA
+// certificates and the client certificate are those of the environment variables if the context
+// does not implement plugin.TLSSettings.
+func TLSOptionsFromContext(context plugin.PluginContext) TLSOptions {
+	opts := TLSOptionsFromEnv()
+	if settings, ok := context.(plugin.TLSSettings); ok {
+		opts = TLSOptions{
+			CACertFiles:    settings.CACertFiles(),
+			ClientCertFile: settings.ClientCertFile(),
+			ClientKeyFile:  settings.ClientKeyFile(),
+		}
+	}
+	opts.InsecureSkipVerify = context.IsSSLDisabled()
+	return opts
+}
+
+// NewTransport returns a transport configured with the TLS settings. It honors the proxy
+// environment variables, the additional CA certificates and the client certificate, and skips
+// the TLS verification if it is disabled.
+func NewTransport(opts TLSOptions) (*http.Transport, error) {
+	tlsConfig, err := NewTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}, nil
+}
+
+// NewHTTPClient returns an HTTP client configured from the plugin context:
+//   - the timeout is the HTTP timeout of the CLI, in seconds
+//   - the TLS verification is skipped if SSL validation is disabled
+//   - additional CA certificates and the client certificate for mutual TLS are applied, from the
+//     options if set, otherwise from the plugin context
+//   - proxies are taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+//   - requests and responses are traced if trace is enabled
+//   - exchanges are recorded to the HAR file set by the environment variable 'IBMCLOUD_TRACE_HAR'
+//   - a User-Agent identifying the CLI, the plugin and the SDK is sent
+//   - an X-Request-Id is generated for requests without one
+//   - GET responses are cached on disk if enabled by the options
+//
+// An error is returned if the CA certificates or the client certificate are invalid.
+func NewHTTPClient(context plugin.PluginContext, opts ClientOptions) (*http.Client, error) {
+	tlsOpts := TLSOptionsFromContext(context)
+	if opts.TLS != nil {
+		tlsOpts = *opts.TLS
+	}
+	transport, err := NewTransport(tlsOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var rt http.RoundTripper = transport
+	if traceEnabled(context.Trace()) {
+		rt = NewTraceLoggingTransport(rt)
+	}
+	if recorder := DefaultHARRecorder(); recorder != nil {
+		rt = NewHARTransport(rt, recorder)
+	}
+	if opts.Cache {
+		rt = NewDefaultCacheTransport(rt, context.CurrentAccount().GUID)
+	}
+	rt = &headerTransport{
+		rt:        rt,
+		userAgent: UserAgent(context.CLIName(), opts),
+	}
+
+	return &http.Client{
+		Transport: rt,
+		Timeout:   time.Duration(context.HTTPTimeout()) * time.Second,
+	}, nil
+}
+
+// NewRESTClient returns a REST client using the HTTP client returned by NewHTTPClient
+func NewRESTClient(context plugin.PluginContext, opts ClientOptions) (*rest.Client, error) {
+	client, err := NewHTTPClient(context, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &rest.Client{
+		HTTPClient:    client,
+		DefaultHeader: make(http.Header),
+	}, nil
+}
+
+// UserAgent returns the User-Agent of requests sent by the plugin, for example
+// 'ibmcloud/2.30.0 (my-plugin/1.0.0) ibm-cloud-cli-sdk/1.12.1 go1.22.1 linux/amd64'
+func UserAgent(cliName string, opts ClientOptions) string {
+	if cliName == "" {
+		cliName = "ibmcloud"
+	}
+
+	var b strings.Builder
+	b.WriteString(cliName)
+	if opts.CLIVersion != "" {
+		b.WriteString("/" + opts.CLIVersion)
+	}
+	if opts.PluginName != "" {
+		b.WriteString(" (" + opts.PluginName)
+		if opts.PluginVersion != (plugin.VersionType{}) {
+			b.WriteString("/" + opts.PluginVersion.String())
+		}
+		b.WriteString(")")
+	}
+	fmt.Fprintf(&b, " ibm-cloud-cli-sdk/%s %s %s/%s", bluemix.Version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return b.String()
+}
+
+func traceEnabled(setting string) bool {
+	return setting != "" && !strings.EqualFold(setting, "false")
+}
+
+// headerTransport sets the User-Agent and the X-Request-Id of requests which have none
+type headerTransport struct {
+	rt        http.RoundTripper
+	userAgent string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	setUserAgent := req.Header.Get("User-Agent") == ""
+	setRequestID := req.Header.Get(RequestIDHeader) == ""
+	if setUserAgent || setRequestID {
+		// a RoundTripper must not modify the request
+		req = req.Clone(req.Context())
+		if req.Header == nil {
+			req.Header = make(http.Header)
+		}
+		if setUserAgent {
+			req.Header.Set("User-Agent", t.userAgent)
+		}
+		if setRequestID {
+			req.Header.Set(RequestIDHeader, uuid.NewString())
+		}
+	}
+	return t.rt.RoundTrip(req)
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/common/rest"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin/pluginfakes"
+)
+
+func TestUserAgent(t *testing.T) {
+	opts := ClientOptions{
+		PluginName:    "my-plugin",
+		PluginVersion: plugin.VersionType{Major: 1, Minor: 2, Build: 3},
+		CLIVersion:    "2.30.0",
+	}
+	expected := "ibmcloud/2.30.0 (my-plugin/1.2.3) ibm-cloud-cli-sdk/" + bluemix.Version.String() + " " + runtime.Version() + " " + runtime.GOOS + "/" + runtime.GOARCH
+	assert.Equal(t, expected, UserAgent("ibmcloud", opts))
+	assert.Equal(t, "ibmcloud ibm-cloud-cli-sdk/"+bluemix.Version.String()+" "+runtime.Version()+" "+runtime.GOOS+"/"+runtime.GOARCH, UserAgent("", ClientOptions{}))
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	context := new(pluginfakes.FakePluginContext)
+	context.HTTPTimeoutReturns(30)
+	context.IsSSLDisabledReturns(true)
+	context.CLINameReturns("ibmcloud")
+
+	client, err := NewHTTPClient(context, ClientOptions{PluginName: "my-plugin"})
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, client.Timeout)
+
+	headers := client.Transport.(*headerTransport)
+	transport, ok := headers.rt.(*http.Transport)
+	if assert.True(t, ok, "no trace transport is expected") {
+		assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+		assert.NotNil(t, transport.Proxy)
+	}
+
+	context.TraceReturns("true")
+	client, err = NewHTTPClient(context, ClientOptions{})
+	assert.NoError(t, err)
+	_, ok = client.Transport.(*headerTransport).rt.(*TraceLoggingTransport)
+	assert.True(t, ok, "a trace transport is expected")
+}
+
+func TestNewRESTClientHeaders(t *testing.T) {
+	var requests []*http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+	}))
+	defer ts.Close()
+
+	context := new(pluginfakes.FakePluginContext)
+	context.CLINameReturns("ibmcloud")
+	client, err := NewRESTClient(context, ClientOptions{PluginName: "my-plugin"})
+	assert.NoError(t, err)
+
+	_, err = client.Do(rest.GetRequest(ts.URL), nil, nil)
+	assert.NoError(t, err)
+	_, err = client.Do(rest.GetRequest(ts.URL).Set("User-Agent", "custom").Set(RequestIDHeader, "my-id"), nil, nil)
+	assert.NoError(t, err)
+
+	if assert.Len(t, requests, 2) {
+		assert.Contains(t, requests[0].UserAgent(), "ibmcloud (my-plugin) ibm-cloud-cli-sdk/")
+		assert.Len(t, requests[0].Header.Get(RequestIDHeader), 36)
+		assert.Equal(t, "custom", requests[1].UserAgent())
+		assert.Equal(t, "my-id", requests[1].Header.Get(RequestIDHeader))
+	}
+}
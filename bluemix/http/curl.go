@@ -0,0 +1,97 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
+)
+
+// CurlMode controls whether TraceLoggingTransport prints requests as curl commands
+type CurlMode int
+
+const (
+	// CurlDisabled does not print curl commands
+	CurlDisabled CurlMode = iota
+	// CurlEnabled prints curl commands with sensitive data redacted
+	CurlEnabled
+	// CurlTokenPlaceholder prints curl commands with the credentials of the Authorization
+	// header replaced by the shell variable $TOKEN, so that the command can be run as is
+	CurlTokenPlaceholder
+)
+
+// CurlModeFromEnv returns the curl mode set by the environment variable 'IBMCLOUD_TRACE_CURL':
+// 'true' for CurlEnabled and 'token' for CurlTokenPlaceholder
+func CurlModeFromEnv() CurlMode {
+	switch strings.ToLower(bluemix.EnvTraceCurl.Get()) {
+	case "true":
+		return CurlEnabled
+	case "token":
+		return CurlTokenPlaceholder
+	}
+	return CurlDisabled
+}
+
+// CurlCommand returns the curl command line equivalent to the request. Header values, the URL
+// and the body are redacted with trace.Sanitize, multipart and binary bodies are replaced by
+// a placeholder.
+func CurlCommand(req *http.Request, body []byte, mode CurlMode) string {
+	command := "curl"
+	switch req.Method {
+	case "", http.MethodGet:
+	case http.MethodHead:
+		// '-X HEAD' makes curl wait for a response body that never comes
+		command += " -I"
+	default:
+		command += " -X " + req.Method
+	}
+	args := []string{command + " " + shellQuote(trace.Sanitize(req.URL.String()))}
+
+	if req.Host != "" && req.Host != req.URL.Host {
+		args = append(args, "-H "+shellQuote("Host: "+req.Host))
+	}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sanitized := sanitizeHeader(req.Header)
+	for _, name := range names {
+		if name == "Content-Length" {
+			continue
+		}
+		for i, v := range sanitized[name] {
+			if mode == CurlTokenPlaceholder && http.CanonicalHeaderKey(name) == "Authorization" {
+				// double quotes so that the shell expands the variable
+				scheme, _, found := strings.Cut(req.Header[name][i], " ")
+				token := "$TOKEN"
+				if found {
+					token = scheme + " $TOKEN"
+				}
+				args = append(args, `-H "`+name+": "+token+`"`)
+				continue
+			}
+			args = append(args, "-H "+shellQuote(name+": "+v))
+		}
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "multipart/form-data"):
+		args = append(args, "--data-binary "+shellQuote("[MULTIPART/FORM-DATA CONTENT HIDDEN]"))
+	case strings.Contains(contentType, "octet-stream") && len(body) > 0:
+		args = append(args, "--data-binary "+shellQuote("[BINARY OCTET-STREAM CONTENT HIDDEN]"))
+	case len(body) > 0:
+		args = append(args, "--data-binary "+shellQuote(trace.Sanitize(string(body))))
+	}
+
+	return strings.Join(args, " \\\n  ")
+}
+
+// shellQuote quotes the string for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurlCommand(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com/v1/items?apikey=my-key&limit=10", nil)
+	req.Header.Set("Authorization", "Bearer my-token")
+	req.Header.Set("Content-Type", "application/json")
+	body := []byte(`{"name": "it's mine", "password": "secret"}`)
+
+	expected := `curl -X POST 'https://example.com/v1/items?apikey=[PRIVATE DATA HIDDEN]&limit=10' \
+  -H 'Authorization: [PRIVATE DATA HIDDEN]' \
+  -H 'Content-Type: application/json' \
+  --data-binary '{"name": "it'\''s mine", "password":"[PRIVATE DATA HIDDEN]"}'`
+	assert.Equal(t, expected, CurlCommand(req, body, CurlEnabled))
+
+	cmd := CurlCommand(req, body, CurlTokenPlaceholder)
+	assert.Contains(t, cmd, `-H "Authorization: Bearer $TOKEN"`)
+	assert.NotContains(t, cmd, "my-token")
+
+	get, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	assert.Equal(t, "curl 'https://example.com/'", CurlCommand(get, nil, CurlEnabled))
+
+	head, _ := http.NewRequest(http.MethodHead, "https://example.com/", nil)
+	assert.Equal(t, "curl -I 'https://example.com/'", CurlCommand(head, nil, CurlEnabled))
+}
+
+func TestCurlCommandMultipart(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPut, "https://example.com/upload", nil)
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+	assert.Contains(t, CurlCommand(req, nil, CurlEnabled), "--data-binary '[MULTIPART/FORM-DATA CONTENT HIDDEN]'")
+}
+
+func TestCurlCommandShellQuoting(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no POSIX shell")
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com/", nil)
+	body := []byte(`'single' "double" $HOME \back` + "`tick`")
+	// replace curl with printf to get the arguments as parsed by the shell
+	cmd := strings.Replace(CurlCommand(req, body, CurlEnabled), "curl", `printf '%s\n'`, 1)
+	out, err := exec.Command(sh, "-c", cmd).Output()
+	assert.NoError(t, err)
+	assert.Equal(t, "-X\nPOST\nhttps://example.com/\n--data-binary\n"+string(body)+"\n", string(out))
+}
+
+func (suite *TransportTestSuite) TestTraceCurl() {
+	suite.T().Setenv("IBMCLOUD_TRACE_CURL", "token")
+	suite.client = &http.Client{Transport: NewTraceLoggingTransport(nil)}
+
+	var contentLengths []int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLengths = append(contentLengths, r.ContentLength)
+	}))
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"name": "foo"}`))
+	req.Header.Set("Authorization", "Bearer my-token")
+	resp, err := suite.client.Do(req)
+	suite.NoError(err)
+	resp.Body.Close()
+
+	dump := string(suite.logger.Dump())
+	suite.Contains(dump, "CURL:")
+	suite.Contains(dump, `-H "Authorization: Bearer $TOKEN"`)
+	suite.Contains(dump, `--data-binary '{"name": "foo"}'`)
+	suite.NotContains(dump, "my-token")
+	// the request body is still sent
+	suite.Equal([]int64{15}, contentLengths)
+}
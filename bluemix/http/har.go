@@ -0,0 +1,438 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
+	. "github.com/IBM-Cloud/ibm-cloud-cli-sdk/i18n"
+)
+
+// HAR is an HTTP Archive 1.2 document, see http://www.softwareishard.com/blog/har-12-spec/
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root of the exported data
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator is the application that created the log
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is an HTTP exchange
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	Connection      string      `json:"connection,omitempty"`
+}
+
+// HARRequest is the request of an exchange
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARPostData is the body of a request
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARResponse is the response of an exchange
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARContent is the body of a response
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// HARNameValue is a header, cookie or query string parameter
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARTimings are the durations in milliseconds of the phases of an exchange, -1 if a phase does not apply
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// maxHARContentSize is the maximum size of a response body kept in a HAR file, larger bodies
+// are truncated
+const maxHARContentSize = 1 << 20
+
+// harTrailer closes the entries array and the log of a HAR file
+const harTrailer = "\n  ]\n}}\n"
+
+// HARRecorder collects HTTP exchanges and appends each of them to a HAR file, so that the file
+// is complete whenever the command exits. Headers, URLs and bodies are redacted with
+// trace.Sanitize. A HARRecorder is safe for concurrent use.
+type HARRecorder struct {
+	path string
+
+	mu   sync.Mutex
+	har  HAR
+	size int64 // size of the HAR file written so far, 0 if it is not created yet
+}
+
+var (
+	defaultHARRecorders   = make(map[string]*HARRecorder)
+	defaultHARRecordersMu sync.Mutex
+)
+
+// NewHARRecorder returns a recorder writing to the HAR file of the given path
+func NewHARRecorder(path string) *HARRecorder {
+	return &HARRecorder{
+		path: path,
+		har: HAR{Log: HARLog{
+			Version: "1.2",
+			Creator: HARCreator{Name: "ibm-cloud-cli-sdk", Version: bluemix.Version.String()},
+			Entries: []HAREntry{},
+		}},
+	}
+}
+
+// DefaultHARRecorder returns the recorder of the HAR file set by the environment variable
+// 'IBMCLOUD_TRACE_HAR', or nil if not set. All clients of the process share the same recorder.
+func DefaultHARRecorder() *HARRecorder {
+	path := bluemix.EnvTraceHAR.Get()
+	if path == "" {
+		return nil
+	}
+
+	defaultHARRecordersMu.Lock()
+	defer defaultHARRecordersMu.Unlock()
+	r, ok := defaultHARRecorders[path]
+	if !ok {
+		r = NewHARRecorder(path)
+		defaultHARRecorders[path] = r
+	}
+	return r
+}
+
+// Entries returns the recorded exchanges
+func (r *HARRecorder) Entries() []HAREntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]HAREntry(nil), r.har.Log.Entries...)
+}
+
+// Add records an exchange and appends it to the HAR file. The file is created by the first
+// exchange, the next ones overwrite the end of the entries array only.
+func (r *HARRecorder) Add(entry HAREntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.har.Log.Entries = append(r.har.Log.Entries, entry)
+
+	data, err := json.MarshalIndent(entry, "    ", "  ")
+	if err != nil {
+		return err
+	}
+
+	if r.size == 0 {
+		head, err := r.head()
+		if err != nil {
+			return err
+		}
+		content := head + "    " + string(data) + harTrailer
+		if err := os.WriteFile(filepath.Clean(r.path), []byte(content), 0600); err != nil {
+			return err
+		}
+		r.size = int64(len(content))
+		return nil
+	}
+
+	f, err := os.OpenFile(filepath.Clean(r.path), os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	content := ",\n    " + string(data) + harTrailer
+	if _, err := f.WriteAt([]byte(content), r.size-int64(len(harTrailer))); err != nil {
+		return err
+	}
+	r.size += int64(len(content) - len(harTrailer))
+	return nil
+}
+
+// head returns the beginning of the HAR file, up to the opening of the entries array
+func (r *HARRecorder) head() (string, error) {
+	version, err := json.Marshal(r.har.Log.Version)
+	if err != nil {
+		return "", err
+	}
+	creator, err := json.Marshal(r.har.Log.Creator)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("{\"log\": {\n  \"version\": %s,\n  \"creator\": %s,\n  \"entries\": [\n", version, creator), nil
+}
+
+// HARTransport is a RoundTripper recording each exchange to a HARRecorder
+//
+// Example:
+//   client := &gohttp.Client{
+//       Transport: http.NewHARTransport(nil, http.NewHARRecorder("ibmcloud.har")),
+//   }
+type HARTransport struct {
+	rt       http.RoundTripper
+	recorder *HARRecorder
+}
+
+// NewHARTransport creates a HARTransport wrapping around the passed RoundTripper. If the passed
+// RoundTripper is nil, HTTP DefaultTransport is used.
+func NewHARTransport(rt http.RoundTripper, recorder *HARRecorder) *HARTransport {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &HARTransport{rt: rt, recorder: recorder}
+}
+
+func (t *HARTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		// a RoundTripper must not modify the request, the body is replaced on a copy of it
+		req = req.Clone(req.Context())
+		var err error
+		if reqBody, err = readBody(&req.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	timedReq, timing := withTiming(req)
+	resp, err := t.rt.RoundTrip(timedReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// the exchange is recorded once the caller has read or closed the response body
+	body := &harResponseBody{ReadCloser: resp.Body, start: time.Now()}
+	body.done = func() {
+		end := time.Now()
+		entry := newHAREntry(req, reqBody, resp, body.content.Bytes(), body.size, timing, end.Sub(body.start), end)
+		if err := t.recorder.Add(entry); err != nil {
+			trace.Logger.Print(T("An error occurred while writing HAR file '{{.Path}}':\n{{.Error}}\n", map[string]interface{}{"Path": t.recorder.path, "Error": err.Error()}))
+		}
+	}
+	if resp.Body == nil {
+		body.record()
+		return resp, nil
+	}
+	resp.Body = body
+	return resp, nil
+}
+
+// harResponseBody copies the response body up to maxHARContentSize while the caller reads it,
+// and records the exchange at the end of the body or when it is closed
+type harResponseBody struct {
+	io.ReadCloser
+
+	start   time.Time
+	content bytes.Buffer
+	size    int
+	once    sync.Once
+	done    func()
+}
+
+func (b *harResponseBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += n
+	if room := maxHARContentSize - b.content.Len(); room > 0 {
+		b.content.Write(p[:min(n, room)])
+	}
+	if err == io.EOF {
+		b.record()
+	}
+	return n, err
+}
+
+func (b *harResponseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.record()
+	return err
+}
+
+func (b *harResponseBody) record() {
+	b.once.Do(b.done)
+}
+
+func newHAREntry(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, respSize int, timing *requestTiming, receive time.Duration, end time.Time) HAREntry {
+	phases := timing.phases()
+	connect := phases.Connect
+	if connect >= 0 && phases.TLS > 0 {
+		// the connect time includes the TLS handshake in HAR
+		connect += phases.TLS
+	}
+
+	entry := HAREntry{
+		StartedDateTime: timing.start.Format(time.RFC3339Nano),
+		Time:            milliseconds(end.Sub(timing.start)),
+		Request: HARRequest{
+			Method:      req.Method,
+			URL:         trace.Sanitize(req.URL.String()),
+			HTTPVersion: protoOrDefault(req.Proto),
+			Cookies:     []HARNameValue{},
+			Headers:     harHeaders(req.Header),
+			QueryString: harQueryString(req),
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: HARResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: protoOrDefault(resp.Proto),
+			Cookies:     []HARNameValue{},
+			Headers:     harHeaders(resp.Header),
+			Content:     harContent(resp.Header.Get("Content-Type"), respBody, respSize),
+			RedirectURL: resp.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    respSize,
+		},
+		Timings: HARTimings{
+			Blocked: milliseconds(phases.Blocked),
+			DNS:     milliseconds(phases.DNS),
+			Connect: milliseconds(connect),
+			Send:    milliseconds(phases.Send),
+			Wait:    milliseconds(phases.Wait),
+			Receive: milliseconds(receive),
+			SSL:     milliseconds(phases.TLS),
+		},
+	}
+	if host, _, err := net.SplitHostPort(phases.RemoteAddr); err == nil {
+		entry.ServerIPAddress = host
+	}
+	if phases.RemoteAddr != "" {
+		entry.Connection = phases.RemoteAddr
+	}
+	// send and wait are required
+	if entry.Timings.Send < 0 {
+		entry.Timings.Send = 0
+	}
+	if entry.Timings.Wait < 0 {
+		entry.Timings.Wait = 0
+	}
+
+	if len(reqBody) > 0 {
+		mimeType := req.Header.Get("Content-Type")
+		entry.Request.PostData = &HARPostData{MimeType: mimeType, Text: harBodyText(mimeType, reqBody)}
+	}
+	return entry
+}
+
+func harHeaders(header http.Header) []HARNameValue {
+	ret := []HARNameValue{}
+	for name, values := range sanitizeHeader(header) {
+		for _, v := range values {
+			ret = append(ret, HARNameValue{Name: name, Value: v})
+		}
+	}
+	sortNameValues(ret)
+	return ret
+}
+
+func sortNameValues(nvs []HARNameValue) {
+	sort.SliceStable(nvs, func(i, j int) bool { return nvs[i].Name < nvs[j].Name })
+}
+
+func harQueryString(req *http.Request) []HARNameValue {
+	ret := []HARNameValue{}
+	for name, values := range req.URL.Query() {
+		for _, v := range values {
+			// sanitize as a query string so that the same parameters are redacted as in the URL
+			sanitized := trace.Sanitize(name + "=" + v)
+			ret = append(ret, HARNameValue{Name: name, Value: strings.TrimPrefix(sanitized, name+"=")})
+		}
+	}
+	sortNameValues(ret)
+	return ret
+}
+
+// harContent returns the content of a response body of the given size, of which body is the
+// beginning if the body is larger than maxHARContentSize
+func harContent(mimeType string, body []byte, size int) HARContent {
+	content := HARContent{Size: size, MimeType: mimeType}
+	if text := harBodyText(mimeType, body); text != "" {
+		content.Text = text
+		if size > len(body) {
+			content.Comment = fmt.Sprintf("content truncated to %d bytes", len(body))
+		}
+	} else if len(body) > 0 {
+		content.Comment = "binary content omitted"
+	}
+	return content
+}
+
+// harBodyText returns the sanitized body, or an empty string for binary or multipart content
+func harBodyText(mimeType string, body []byte) string {
+	if strings.Contains(mimeType, "octet-stream") {
+		return ""
+	}
+	if strings.Contains(mimeType, "multipart/form-data") {
+		return "[MULTIPART/FORM-DATA CONTENT HIDDEN]"
+	}
+	return trace.Sanitize(string(body))
+}
+
+func protoOrDefault(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+func milliseconds(d time.Duration) float64 {
+	if d < 0 {
+		return -1
+	}
+	return float64(d) / float64(time.Millisecond)
+}
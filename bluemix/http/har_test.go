@@ -0,0 +1,167 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin/pluginfakes"
+)
+
+func TestHARTransport(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"refresh_token": "secret", "name": "foo"}`)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "trace.har")
+	recorder := NewHARRecorder(path)
+	client := &http.Client{Transport: NewHARTransport(ts.Client().Transport, recorder)}
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/items?apikey=secret&limit=10", strings.NewReader(`{"password": "secret"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// the caller still gets the response body
+	assert.Contains(t, string(body), `"refresh_token": "secret"`)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+
+	var har HAR
+	assert.NoError(t, json.Unmarshal(data, &har))
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Equal(t, bluemix.Version.String(), har.Log.Creator.Version)
+	if assert.Len(t, har.Log.Entries, 1) {
+		entry := har.Log.Entries[0]
+		assert.Equal(t, http.MethodPost, entry.Request.Method)
+		assert.Contains(t, entry.Request.URL, "apikey=[PRIVATE DATA HIDDEN]")
+		assert.Equal(t, []HARNameValue{{"apikey", "[PRIVATE DATA HIDDEN]"}, {"limit", "10"}}, entry.Request.QueryString)
+		assert.Contains(t, entry.Request.Headers, HARNameValue{"Authorization", "[PRIVATE DATA HIDDEN]"})
+		assert.Equal(t, "application/json", entry.Request.PostData.MimeType)
+		assert.Equal(t, http.StatusOK, entry.Response.Status)
+		assert.Equal(t, "application/json", entry.Response.Content.MimeType)
+		assert.Contains(t, entry.Response.Content.Text, `"name": "foo"`)
+		assert.Equal(t, "127.0.0.1", entry.ServerIPAddress)
+
+		// a new TLS connection is established
+		assert.True(t, entry.Timings.Connect >= 0)
+		assert.True(t, entry.Timings.SSL >= 0)
+		assert.True(t, entry.Timings.Connect >= entry.Timings.SSL)
+		assert.True(t, entry.Timings.Wait >= 0)
+		assert.True(t, entry.Time >= entry.Timings.Wait)
+	}
+
+	// the connection is reused
+	resp, err = client.Get(ts.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	entries := recorder.Entries()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, float64(-1), entries[1].Timings.DNS)
+		assert.Equal(t, float64(-1), entries[1].Timings.Connect)
+		assert.Equal(t, float64(-1), entries[1].Timings.SSL)
+	}
+}
+
+func TestHARTransportKeepsRequestBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	}))
+	defer ts.Close()
+
+	transport := NewHARTransport(nil, NewHARRecorder(filepath.Join(t.TempDir(), "trace.har")))
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader("foo"))
+	body := req.Body
+	resp, err := transport.RoundTrip(req)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "foo", string(data))
+	assert.Equal(t, body, req.Body)
+}
+
+func TestHARTransportFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.har")
+	t.Setenv("IBMCLOUD_TRACE_HAR", path)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte{0, 1, 2})
+	}))
+	defer ts.Close()
+
+	client, err := NewHTTPClient(new(pluginfakes.FakePluginContext), ClientOptions{})
+	assert.NoError(t, err)
+	resp, err := client.Get(ts.URL)
+	assert.NoError(t, err)
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	entries := DefaultHARRecorder().Entries()
+	if assert.Len(t, entries, 1) {
+		assert.Empty(t, entries[0].Response.Content.Text)
+		assert.Equal(t, 3, entries[0].Response.Content.Size)
+		assert.Contains(t, entries[0].Request.Headers, HARNameValue{"User-Agent", UserAgent("", ClientOptions{})})
+	}
+	assert.FileExists(t, path)
+}
+
+func TestHARTransportLargeBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(strings.Repeat("a", maxHARContentSize+10)))
+	}))
+	defer ts.Close()
+
+	recorder := NewHARRecorder(filepath.Join(t.TempDir(), "trace.har"))
+	client := &http.Client{Transport: NewHARTransport(nil, recorder)}
+
+	resp, err := client.Get(ts.URL)
+	assert.NoError(t, err)
+	// the exchange is recorded once the body is read
+	assert.Empty(t, recorder.Entries())
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Len(t, body, maxHARContentSize+10)
+
+	entries := recorder.Entries()
+	if assert.Len(t, entries, 1) {
+		content := entries[0].Response.Content
+		assert.Equal(t, maxHARContentSize+10, content.Size)
+		assert.Len(t, content.Text, maxHARContentSize)
+		assert.Equal(t, fmt.Sprintf("content truncated to %d bytes", maxHARContentSize), content.Comment)
+	}
+}
+
+func TestHARRecorderAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.har")
+	recorder := NewHARRecorder(path)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, recorder.Add(HAREntry{Request: HARRequest{URL: fmt.Sprintf("https://cloud.ibm.com/%d", i)}}))
+
+		// the file is a complete HAR document after each exchange
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		var har HAR
+		if assert.NoError(t, json.Unmarshal(data, &har)) && assert.Len(t, har.Log.Entries, i+1) {
+			assert.Equal(t, "1.2", har.Log.Version)
+			assert.Equal(t, fmt.Sprintf("https://cloud.ibm.com/%d", i), har.Log.Entries[i].Request.URL)
+		}
+	}
+}
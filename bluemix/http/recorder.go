@@ -0,0 +1,258 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
+)
+
+// RecorderMode is the mode of a Recorder
+type RecorderMode int
+
+const (
+	// ModeRecord sends requests to the server and records the interactions
+	ModeRecord RecorderMode = iota
+	// ModeReplay serves the recorded interactions without contacting the server
+	ModeReplay
+)
+
+// Cassette is the set of recorded HTTP interactions, saved as a JSON file
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request with sensitive data redacted
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response with sensitive data redacted
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// MatcherFunc reports whether a request matches a recorded request. The request body is
+// passed separately since the request body can be read only once.
+type MatcherFunc func(req *http.Request, body []byte, recorded RecordedRequest) bool
+
+// DefaultMatcher matches requests by method and URL
+func DefaultMatcher(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	return req.Method == recorded.Method && trace.Sanitize(req.URL.String()) == recorded.URL
+}
+
+// BodyMatcher matches requests by method, URL and body
+func BodyMatcher(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	return DefaultMatcher(req, body, recorded) && trace.Sanitize(string(body)) == recorded.Body
+}
+
+// Recorder is a RoundTripper recording HTTP interactions to a cassette file or replaying them,
+// so that tests can run against a snapshot of a real API conversation. Recorded headers, URLs
+// and bodies are redacted with trace.Sanitize.
+//
+// Example:
+//   recorder, err := http.NewRecorder("testdata/list_instances.json", http.ModeReplay, nil)
+//   client := rest.NewClient()
+//   client.HTTPClient = &gohttp.Client{Transport: recorder}
+//   ...
+//   recorder.Stop()
+type Recorder struct {
+	// Matcher selects the recorded interaction of a request in replay mode, default is DefaultMatcher
+	Matcher MatcherFunc
+
+	rt   http.RoundTripper
+	mode RecorderMode
+	path string
+
+	mu       sync.Mutex
+	cassette Cassette
+	replayed []bool
+}
+
+// NewRecorder creates a Recorder of the cassette file. In replay mode the cassette file is
+// loaded. In record mode requests are sent with the passed RoundTripper, or HTTP
+// DefaultTransport if nil, and the cassette file is written by Stop.
+func NewRecorder(path string, mode RecorderMode, rt http.RoundTripper) (*Recorder, error) {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	r := &Recorder{
+		Matcher: DefaultMatcher,
+		rt:      rt,
+		mode:    mode,
+		path:    path,
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read cassette '%s': %v", path, err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("invalid cassette '%s': %v", path, err)
+		}
+		r.replayed = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Interactions returns the recorded interactions
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+// Stop writes the cassette file in record mode
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0600)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		// a RoundTripper must not modify the request, the body is replaced on a copy of it
+		req = req.Clone(req.Context())
+		var err error
+		if body, err = readBody(&req.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    trace.Sanitize(req.URL.String()),
+			Header: sanitizeHeader(req.Header),
+			Body:   trace.Sanitize(string(body)),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     sanitizeHeader(resp.Header),
+			Body:       trace.Sanitize(string(respBody)),
+		},
+	}
+	// the length of the sanitized bodies differs, it is computed again on replay
+	interaction.Request.Header.Del("Content-Length")
+	interaction.Response.Header.Del("Content-Length")
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	matcher := r.Matcher
+	if matcher == nil {
+		matcher = DefaultMatcher
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// interactions are replayed once and in order, so that the same request can get different responses
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] || !matcher(req, body, interaction.Request) {
+			continue
+		}
+		r.replayed[i] = true
+
+		recorded := interaction.Response
+		header := recorded.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		// cassettes recorded by previous versions may have the length of the unsanitized body
+		header.Set("Content-Length", strconv.Itoa(len(recorded.Body)))
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction in cassette '%s' matches request %s %s", r.path, req.Method, trace.Sanitize(req.URL.String()))
+}
+
+// readBody reads the body and replaces it so that it can be read again
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// sanitizeHeader redacts the header values the same way as the trace output
+func sanitizeHeader(header http.Header) http.Header {
+	if header == nil {
+		return nil
+	}
+	ret := make(http.Header, len(header))
+	for name, values := range header {
+		for _, v := range values {
+			line := trace.Sanitize(name + ": " + v)
+			ret[name] = append(ret[name], strings.TrimPrefix(line, name+": "))
+		}
+	}
+	return ret
+}
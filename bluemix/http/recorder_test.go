@@ -0,0 +1,144 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/common/rest"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"access_token": "secret", "name": "created"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"call": %d}`, calls)
+	}))
+	defer ts.Close()
+
+	cassette := filepath.Join(t.TempDir(), "fixtures", "cassette.json")
+	recorder, err := NewRecorder(cassette, ModeRecord, nil)
+	assert.NoError(t, err)
+	client := &rest.Client{HTTPClient: &http.Client{Transport: recorder}}
+
+	var v map[string]interface{}
+	_, err = client.Do(rest.GetRequest(ts.URL+"/items").Set("Authorization", "Bearer secret"), &v, nil)
+	assert.NoError(t, err)
+	_, err = client.Do(rest.GetRequest(ts.URL+"/items"), &v, nil)
+	assert.NoError(t, err)
+	_, err = client.Do(rest.PostRequest(ts.URL+"/items").Body(`{"name": "created"}`), &v, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", v["access_token"])
+	assert.NoError(t, recorder.Stop())
+
+	data, err := os.ReadFile(cassette)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	assert.Contains(t, string(data), "[PRIVATE DATA HIDDEN]")
+
+	// replay without server
+	ts.Close()
+	recorder, err = NewRecorder(cassette, ModeReplay, nil)
+	assert.NoError(t, err)
+	recorder.Matcher = BodyMatcher
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	_, err = client.Do(rest.PostRequest(ts.URL+"/items").Body(`{"name": "created"}`), &v, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "created", v["name"])
+
+	// the same request is replayed in the recorded order
+	_, err = client.Do(rest.GetRequest(ts.URL+"/items"), &v, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), v["call"])
+	_, err = client.Do(rest.GetRequest(ts.URL+"/items"), &v, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), v["call"])
+
+	_, err = client.Do(rest.GetRequest(ts.URL+"/items"), &v, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no recorded interaction")
+	}
+}
+
+func TestRecorderReplayCassette(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	assert.NoError(t, os.WriteFile(cassette, []byte(`{
+  "interactions": [
+    {
+      "request": {"method": "GET", "url": "https://example.com/v1/token?apikey=[PRIVATE DATA HIDDEN]"},
+      "response": {"status_code": 404, "header": {"Content-Type": ["text/plain"]}, "body": "not found"}
+    }
+  ]
+}`), 0600))
+
+	recorder, err := NewRecorder(cassette, ModeReplay, nil)
+	assert.NoError(t, err)
+
+	resp, err := (&http.Client{Transport: recorder}).Get("https://example.com/v1/token?apikey=my-key")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "not found", string(body))
+	}
+
+	_, err = NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil)
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(cassette, []byte("{"), 0600))
+	_, err = NewRecorder(cassette, ModeReplay, nil)
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "invalid cassette"))
+	}
+}
+
+func TestRecorderSanitizedContentLength(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	}))
+	defer ts.Close()
+
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, err := NewRecorder(cassette, ModeRecord, nil)
+	assert.NoError(t, err)
+
+	body := io.NopCloser(strings.NewReader(`{"password": "secret"}`))
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, body)
+	resp, err := recorder.RoundTrip(req)
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+	}
+	// the body of the caller's request is not replaced
+	assert.Equal(t, body, req.Body)
+	assert.NoError(t, recorder.Stop())
+
+	interactions := recorder.Interactions()
+	if assert.Len(t, interactions, 1) {
+		assert.Empty(t, interactions[0].Response.Header.Get("Content-Length"))
+	}
+
+	recorder, err = NewRecorder(cassette, ModeReplay, nil)
+	assert.NoError(t, err)
+	resp, err = (&http.Client{Transport: recorder}).Post(ts.URL, "application/json", strings.NewReader(`{"password": "other"}`))
+	if assert.NoError(t, err) {
+		replayed, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(replayed), "[PRIVATE DATA HIDDEN]")
+		assert.Equal(t, strconv.Itoa(len(replayed)), resp.Header.Get("Content-Length"))
+		assert.Equal(t, int64(len(replayed)), resp.ContentLength)
+	}
+}
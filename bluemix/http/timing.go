@@ -0,0 +1,119 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// requestTiming collects the timestamps of the phases of a request with httptrace
+type requestTiming struct {
+	mu sync.Mutex
+
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+
+	reused     bool
+	remoteAddr string
+}
+
+// withTiming returns a copy of the request whose phases are recorded in the returned timing
+func withTiming(req *http.Request) (*http.Request, *requestTiming) {
+	t := &requestTiming{start: time.Now()}
+	set := func(field *time.Time) {
+		t.mu.Lock()
+		// keep the first occurrence, e.g. when dialing multiple addresses
+		if field.IsZero() {
+			*field = time.Now()
+		}
+		t.mu.Unlock()
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { set(&t.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { set(&t.dnsDone) },
+		ConnectStart:      func(string, string) { set(&t.connectStart) },
+		ConnectDone:       func(string, string, error) { set(&t.connectDone) },
+		TLSHandshakeStart: func() { set(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { set(&t.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			set(&t.gotConn)
+			t.mu.Lock()
+			t.reused = info.Reused
+			if info.Conn != nil {
+				t.remoteAddr = info.Conn.RemoteAddr().String()
+			}
+			t.mu.Unlock()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { set(&t.wroteRequest) },
+		GotFirstResponseByte: func() { set(&t.firstByte) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), t
+}
+
+// timingPhases are the durations of the phases of a request. A phase which did not happen,
+// e.g. the DNS lookup of a reused connection, is negative.
+type timingPhases struct {
+	Blocked time.Duration
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	Send    time.Duration
+	Wait    time.Duration
+
+	Reused     bool
+	RemoteAddr string
+}
+
+func (t *requestTiming) phases() timingPhases {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	between := func(from, to time.Time) time.Duration {
+		if from.IsZero() || to.IsZero() {
+			return -1
+		}
+		return to.Sub(from)
+	}
+
+	p := timingPhases{
+		DNS:        between(t.dnsStart, t.dnsDone),
+		Connect:    between(t.connectStart, t.connectDone),
+		TLS:        between(t.tlsStart, t.tlsDone),
+		Send:       between(t.gotConn, t.wroteRequest),
+		Wait:       between(t.wroteRequest, t.firstByte),
+		Reused:     t.reused,
+		RemoteAddr: t.remoteAddr,
+	}
+
+	// the time spent before the connection is established is blocked, except for DNS and connect
+	p.Blocked = between(t.start, t.gotConn)
+	for _, d := range []time.Duration{p.DNS, p.Connect, p.TLS} {
+		if d > 0 && p.Blocked >= 0 {
+			p.Blocked -= d
+		}
+	}
+	if p.Blocked < 0 && !t.gotConn.IsZero() {
+		p.Blocked = 0
+	}
+	return p
+}
+
+// ttfb returns the time from the start of the request to the first byte of the response
+func (t *requestTiming) ttfb() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.firstByte.IsZero() {
+		return -1
+	}
+	return t.firstByte.Sub(t.start)
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix"
+)
+
+// TLSOptions are the TLS settings of the CLI
+type TLSOptions struct {
+	// InsecureSkipVerify disables the verification of the server certificate
+	InsecureSkipVerify bool
+	// CACertFiles are PEM files of CA certificates trusted in addition to the system ones,
+	// e.g. the CA of a TLS-inspecting proxy
+	CACertFiles []string
+	// ClientCertFile and ClientKeyFile are the PEM files of the client certificate and its
+	// private key presented for mutual TLS. Both or none must be set.
+	ClientCertFile string
+	ClientKeyFile  string
+}
+
+// TLSOptionsFromEnv returns the TLS settings of the environment variables 'IBMCLOUD_CA_CERT_FILE',
+// 'IBMCLOUD_CLIENT_CERT_FILE' and 'IBMCLOUD_CLIENT_KEY_FILE', for clients without a plugin context
+func TLSOptionsFromEnv() TLSOptions {
+	opts := TLSOptions{
+		ClientCertFile: bluemix.EnvClientCertFile.Get(),
+		ClientKeyFile:  bluemix.EnvClientKeyFile.Get(),
+	}
+	if v := bluemix.EnvCACertFile.Get(); v != "" {
+		opts.CACertFiles = filepath.SplitList(v)
+	}
+	return opts
+}
+
+// NewTLSConfig returns the TLS configuration of the given settings. It returns an error if a
+// file can not be read or does not contain valid PEM material.
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: opts.InsecureSkipVerify, // #nosec G402 -- disabled explicitly by the user
+	}
+
+	if len(opts.CACertFiles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		for _, file := range opts.CACertFiles {
+			if err := appendCACertFile(pool, file); err != nil {
+				return nil, err
+			}
+		}
+		config.RootCAs = pool
+	}
+
+	if opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		if opts.ClientCertFile == "" || opts.ClientKeyFile == "" {
+			return nil, fmt.Errorf("both the client certificate file and the client key file are required for mutual TLS")
+		}
+		cert, err := tls.LoadX509KeyPair(filepath.Clean(opts.ClientCertFile), filepath.Clean(opts.ClientKeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate '%s' or key '%s': %v", opts.ClientCertFile, opts.ClientKeyFile, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+func appendCACertFile(pool *x509.CertPool, file string) error {
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return fmt.Errorf("unable to read CA certificate file '%s': %v", file, err)
+	}
+
+	found := false
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("invalid certificate in CA certificate file '%s': %v", file, err)
+		}
+		pool.AddCert(cert)
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("no PEM encoded certificate found in CA certificate file '%s'", file)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin/pluginfakes"
+)
+
+func writePEM(t *testing.T, name string, blockType string, bytes []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bytes}), 0600))
+	return path
+}
+
+// newClientCertificate writes a self-signed client certificate and its key and returns their paths
+func newClientCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return writePEM(t, "client.crt", "CERTIFICATE", der), writePEM(t, "client.key", "EC PRIVATE KEY", keyDER)
+}
+
+func TestNewTLSConfigCACertFiles(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	caFile := writePEM(t, "ca.pem", "CERTIFICATE", ts.Certificate().Raw)
+	config, err := NewTLSConfig(TLSOptions{CACertFiles: []string{caFile}})
+	assert.NoError(t, err)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	resp, err := client.Get(ts.URL)
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+	}
+}
+
+func TestNewTLSConfigInvalidCACertFiles(t *testing.T) {
+	_, err := NewTLSConfig(TLSOptions{CACertFiles: []string{filepath.Join(t.TempDir(), "missing.pem")}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unable to read CA certificate file")
+	}
+
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0600))
+	_, err = NewTLSConfig(TLSOptions{CACertFiles: []string{notPEM}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no PEM encoded certificate found")
+	}
+
+	_, err = NewTLSConfig(TLSOptions{CACertFiles: []string{writePEM(t, "bad.pem", "CERTIFICATE", []byte("garbage"))}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid certificate in CA certificate file")
+	}
+}
+
+// tlsContext is a plugin context providing TLS settings
+type tlsContext struct {
+	*pluginfakes.FakePluginContext
+	caCertFiles    []string
+	clientCertFile string
+	clientKeyFile  string
+}
+
+func (c tlsContext) CACertFiles() []string  { return c.caCertFiles }
+func (c tlsContext) ClientCertFile() string { return c.clientCertFile }
+func (c tlsContext) ClientKeyFile() string  { return c.clientKeyFile }
+
+func TestTLSOptionsFromContext(t *testing.T) {
+	t.Setenv("IBMCLOUD_CA_CERT_FILE", "env.pem")
+	fake := new(pluginfakes.FakePluginContext)
+	fake.IsSSLDisabledReturns(true)
+
+	// contexts which do not provide TLS settings use the environment variables
+	assert.Equal(t, TLSOptions{InsecureSkipVerify: true, CACertFiles: []string{"env.pem"}}, TLSOptionsFromContext(fake))
+	assert.Equal(t, TLSOptions{InsecureSkipVerify: true, CACertFiles: []string{"ca.pem"}, ClientCertFile: "client.crt", ClientKeyFile: "client.key"},
+		TLSOptionsFromContext(tlsContext{fake, []string{"ca.pem"}, "client.crt", "client.key"}))
+
+	// the context passed to the plugins provides the TLS settings of the CLI
+	t.Setenv("IBMCLOUD_HOME", t.TempDir())
+	_, ok := plugin.InitPluginContext("demo").(plugin.TLSSettings)
+	assert.True(t, ok)
+}
+
+func TestNewTLSConfigClientCertificate(t *testing.T) {
+	certFile, keyFile := newClientCertificate(t)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Len(t, r.TLS.PeerCertificates, 1)
+		assert.Equal(t, "client", r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	caFile := writePEM(t, "ca.pem", "CERTIFICATE", ts.Certificate().Raw)
+	context := tlsContext{
+		FakePluginContext: new(pluginfakes.FakePluginContext),
+		caCertFiles:       []string{caFile},
+		clientCertFile:    certFile,
+		clientKeyFile:     keyFile,
+	}
+	for _, opts := range []ClientOptions{
+		{},
+		{TLS: &TLSOptions{CACertFiles: []string{caFile}, ClientCertFile: certFile, ClientKeyFile: keyFile}},
+	} {
+		var pluginContext plugin.PluginContext = context
+		if opts.TLS != nil {
+			// the TLS settings of the options are used instead of those of the context
+			pluginContext = context.FakePluginContext
+		}
+		client, err := NewHTTPClient(pluginContext, opts)
+		assert.NoError(t, err)
+		resp, err := client.Get(ts.URL)
+		if assert.NoError(t, err) {
+			resp.Body.Close()
+		}
+	}
+
+	_, err := NewTLSConfig(TLSOptions{ClientCertFile: certFile})
+	assert.Error(t, err)
+
+	_, err = NewTLSConfig(TLSOptions{ClientCertFile: certFile, ClientKeyFile: certFile})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid client certificate")
+	}
+}
+
+func TestTLSOptionsFromEnv(t *testing.T) {
+	t.Setenv("IBMCLOUD_CA_CERT_FILE", "a.pem"+string(os.PathListSeparator)+"b.pem")
+	t.Setenv("IBMCLOUD_CLIENT_CERT_FILE", "client.crt")
+	t.Setenv("IBMCLOUD_CLIENT_KEY_FILE", "client.key")
+
+	assert.Equal(t, TLSOptions{
+		CACertFiles:    []string{"a.pem", "b.pem"},
+		ClientCertFile: "client.crt",
+		ClientKeyFile:  "client.key",
+	}, TLSOptionsFromEnv())
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httputil"
 	"strings"
@@ -14,7 +15,11 @@ import (
 // TraceLoggingTransport is a thin wrapper around Transport.
 // It dumps HTTP request and response using trace logger, created based on the
 // "BLUEMIX_TRACE" environment variable. Sensitive user data will be replaced by
-// text "[PRIVATE DATA HIDDEN]".
+// text "[PRIVATE DATA HIDDEN]". The elapsed time of each response is broken down
+// into DNS lookup, TCP connect, TLS handshake and time to first byte, along with
+// whether the connection was reused and the remote address. Requests are also
+// printed as curl commands if enabled by the "IBMCLOUD_TRACE_CURL" environment
+// variable, see CurlModeFromEnv.
 //
 // Example:
 //   client := &gohttp.Client{ Transport:
@@ -23,6 +28,9 @@ import (
 //   client.Get("http://www.example.com")
 type TraceLoggingTransport struct {
 	rt http.RoundTripper
+
+	// Curl controls whether requests are also printed as curl commands
+	Curl CurlMode
 }
 
 // NewTraceLoggingTransport creates a TraceLoggingTransport wrapping around
@@ -31,22 +39,25 @@ type TraceLoggingTransport struct {
 func NewTraceLoggingTransport(rt http.RoundTripper) *TraceLoggingTransport {
 	if rt == nil {
 		return &TraceLoggingTransport{
-			rt: http.DefaultTransport,
+			rt:   http.DefaultTransport,
+			Curl: CurlModeFromEnv(),
 		}
 	}
 	return &TraceLoggingTransport{
-		rt: rt,
+		rt:   rt,
+		Curl: CurlModeFromEnv(),
 	}
 }
 
 func (r *TraceLoggingTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
 	start := time.Now()
 	r.dumpRequest(req, start)
-	resp, err = r.rt.RoundTrip(req)
+	timedReq, timing := withTiming(req)
+	resp, err = r.rt.RoundTrip(timedReq)
 	if err != nil {
 		return
 	}
-	r.dumpResponse(resp, start)
+	r.dumpResponse(resp, start, timing)
 	return
 }
 
@@ -67,9 +78,28 @@ func (r *TraceLoggingTransport) dumpRequest(req *http.Request, start time.Time)
 	if !shouldDisplayBody {
 		trace.Logger.Println("[MULTIPART/FORM-DATA CONTENT HIDDEN]")
 	}
+
+	if r.Curl != CurlDisabled {
+		r.dumpCurl(req, shouldDisplayBody)
+	}
 }
 
-func (r *TraceLoggingTransport) dumpResponse(res *http.Response, start time.Time) {
+func (r *TraceLoggingTransport) dumpCurl(req *http.Request, withBody bool) {
+	var body []byte
+	if withBody && req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = readBody(&req.Body); err != nil {
+			trace.Logger.Print(T("An error occurred while dumping request:\n{{.Error}}\n", map[string]interface{}{"Error": err.Error()}))
+			return
+		}
+	}
+
+	trace.Logger.Printf("%s\n%s\n",
+		terminal.HeaderColor(T("CURL:")),
+		CurlCommand(req, body, r.Curl))
+}
+
+func (r *TraceLoggingTransport) dumpResponse(res *http.Response, start time.Time, timing *requestTiming) {
 	end := time.Now()
 
 	shouldDisplayBody := !strings.Contains(res.Header.Get("Content-Type"), "octet-stream")
@@ -80,14 +110,34 @@ func (r *TraceLoggingTransport) dumpResponse(res *http.Response, start time.Time
 		return
 	}
 
-	trace.Logger.Printf("\n%s [%s] %s %.0fms\n%s\n",
+	trace.Logger.Printf("\n%s [%s] %s %.0fms\n%s\n%s\n",
 		terminal.HeaderColor(T("RESPONSE:")),
 		end.Format(time.RFC3339),
 		terminal.HeaderColor(T("Elapsed:")),
 		end.Sub(start).Seconds()*1000,
+		formatTiming(timing),
 		trace.Sanitize(string(dumpedResponse)))
 
 	if !shouldDisplayBody {
 		trace.Logger.Println("[SKIP BINARY OCTET-STREAM CONTENT]")
 	}
 }
+
+func formatTiming(timing *requestTiming) string {
+	phases := timing.phases()
+	duration := func(d time.Duration) string {
+		if d < 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.0fms", d.Seconds()*1000)
+	}
+
+	return fmt.Sprintf("%s %s %s, %s %s, %s %s, %s %s, %s %t, %s %s",
+		terminal.HeaderColor(T("Timing:")),
+		T("DNS lookup"), duration(phases.DNS),
+		T("TCP connect"), duration(phases.Connect),
+		T("TLS handshake"), duration(phases.TLS),
+		T("time to first byte"), duration(timing.ttfb()),
+		T("connection reused"), phases.Reused,
+		T("remote address"), phases.RemoteAddr)
+}
@@ -199,3 +199,25 @@ func (suite *TransportTestSuite) TestTraceCustomOctetStream() {
 		suite.Contains(string(suite.logger.Dump()), e)
 	}
 }
+
+func (suite *TransportTestSuite) TestTraceTiming() {
+	ts := httptest.NewTLSServer(http.HandlerFunc(helloHandler))
+	defer ts.Close()
+	suite.client = &http.Client{Transport: NewTraceLoggingTransport(ts.Client().Transport)}
+	host := strings.TrimPrefix(ts.URL, "https://")
+
+	resp, err := suite.client.Get(ts.URL)
+	suite.NoError(err)
+	resp.Body.Close()
+
+	dump := string(suite.logger.Dump())
+	suite.Regexp(`Timing: DNS lookup (-|\d+ms), TCP connect \d+ms, TLS handshake \d+ms, time to first byte \d+ms, connection reused false, remote address `+host, dump)
+
+	suite.logger.Clear()
+	resp, err = suite.client.Get(ts.URL)
+	suite.NoError(err)
+	resp.Body.Close()
+
+	dump = string(suite.logger.Dump())
+	suite.Regexp(`Timing: DNS lookup -, TCP connect -, TLS handshake -, time to first byte \d+ms, connection reused true, remote address `+host, dump)
+}
@@ -0,0 +1,140 @@
+// Command plugin-metadata-validator validates the metadata of IBM Cloud CLI plugins.
+//
+// It runs each plugin binary with the 'SendMetadata' argument, validates the printed metadata with
+// the plugin metadata validator of the SDK and reports the issues as text, JSON or SARIF. The exit
+// status is 1 if an issue has at least the minimum priority, and 2 if a plugin cannot be validated.
+//
+// Usage:
+//
+//	plugin-metadata-validator [-format text|json|sarif] [-min-priority ERROR|WARNING|INFO] [-timeout DURATION] PLUGIN_BINARY...
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin"
+)
+
+const (
+	exitOK = iota
+	exitIssues
+	exitFailure
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("plugin-metadata-validator", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format: text, json or sarif")
+	minPriority := flags.String("min-priority", string(plugin.PriorityError), "minimum priority of the issues failing the validation: ERROR, WARNING or INFO")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout of each plugin binary")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: plugin-metadata-validator [OPTIONS] PLUGIN_BINARY...")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitFailure
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitFailure
+	}
+
+	threshold, ok := priorityRank[plugin.Priority(strings.ToUpper(*minPriority))]
+	if !ok {
+		fmt.Fprintf(stderr, "invalid minimum priority '%s'\n", *minPriority)
+		return exitFailure
+	}
+
+	var write func(io.Writer, []pluginReport) error
+	switch *format {
+	case "text":
+		write = writeText
+	case "json":
+		write = writeJSON
+	case "sarif":
+		write = writeSARIF
+	default:
+		fmt.Fprintf(stderr, "invalid format '%s'\n", *format)
+		return exitFailure
+	}
+
+	var reports []pluginReport
+	for _, binary := range flags.Args() {
+		report, err := validatePlugin(binary, *timeout)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailure
+		}
+		reports = append(reports, report)
+	}
+
+	if err := write(stdout, reports); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+
+	for _, r := range reports {
+		for _, e := range r.Errors {
+			if priorityRank[e.Priority] >= threshold {
+				return exitIssues
+			}
+		}
+	}
+	return exitOK
+}
+
+// priorityRank orders the priorities, the highest being the most severe
+var priorityRank = map[plugin.Priority]int{
+	plugin.PriorityInfo:    1,
+	plugin.PriorityWarning: 2,
+	plugin.PriorityError:   3,
+}
+
+// pluginReport is the validation result of a plugin binary
+type pluginReport struct {
+	Binary string
+	Name   string
+	Errors []plugin.PluginMetadataError
+}
+
+// validatePlugin runs the plugin binary to get its metadata and validates it
+func validatePlugin(binary string, timeout time.Duration) (pluginReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, "SendMetadata")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return pluginReport{}, fmt.Errorf("unable to get the metadata of plugin '%s': %v\n%s", binary, err, stderr.String())
+	}
+
+	resp, err := plugin.ParseHandshakeResponse(stdout.Bytes())
+	if err != nil {
+		return pluginReport{}, fmt.Errorf("unable to get the metadata of plugin '%s': %v", binary, err)
+	}
+
+	report := pluginReport{Binary: binary, Name: resp.Metadata.Name, Errors: []plugin.PluginMetadataError{}}
+	for name, errs := range plugin.NewPluginMetadataValidator().Errors([]plugin.PluginMetadata{resp.Metadata}) {
+		report.Name = name
+		report.Errors = append(report.Errors, errs...)
+	}
+	if report.Name == "" {
+		report.Name = "UNKNOWN"
+	}
+	return report, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+var validMetadata = plugin.PluginMetadata{
+	Name:          "demo",
+	Version:       plugin.VersionType{Major: 1},
+	MinCliVersion: plugin.VersionType{Major: 2},
+	Namespaces:    []plugin.Namespace{{Name: "demo", Description: "Manage demo resources."}},
+	Commands: []plugin.Command{
+		{Namespace: "demo", Name: "list", Description: "List all demo resources.", Usage: "ibmcloud demo list"},
+	},
+}
+
+// fakePlugin writes a script printing the output for the 'SendMetadata' argument
+func fakePlugin(t *testing.T, output string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported")
+	}
+	path := filepath.Join(t.TempDir(), "plugin")
+	script := "#!/bin/sh\n[ \"$1\" = SendMetadata ] || exit 3\ncat <<'EOF'\n" + output + "\nEOF\n"
+	assert.NoError(t, os.WriteFile(path, []byte(script), 0700))
+	return path
+}
+
+func fakePluginWithMetadata(t *testing.T, metadata plugin.PluginMetadata) string {
+	data, _ := json.Marshal(metadata)
+	return fakePlugin(t, string(data))
+}
+
+func invalidMetadata() plugin.PluginMetadata {
+	metadata := validMetadata
+	metadata.Commands = []plugin.Command{
+		{Namespace: "demo", Name: "list", Description: "List all demo resources."},
+	}
+	return metadata
+}
+
+func TestRunValid(t *testing.T) {
+	binary := fakePluginWithMetadata(t, validMetadata)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{binary}, &stdout, &stderr))
+	assert.Equal(t, "demo ("+binary+"): OK\n", stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestRunText(t *testing.T) {
+	binary := fakePluginWithMetadata(t, invalidMetadata())
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitIssues, run([]string{binary}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "demo ("+binary+"): 1 issue(s)\n")
+	assert.Contains(t, stdout.String(), "  ERROR   PluginMetadata.Commands[0].Usage [demo list]: Command 'list' has no usage information.\n")
+	assert.Contains(t, stdout.String(), "          Add usage text showing command syntax with parameters and options.\n")
+}
+
+func TestRunJSON(t *testing.T) {
+	binary := fakePluginWithMetadata(t, invalidMetadata())
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitIssues, run([]string{"-format", "json", binary}, &stdout, &stderr))
+
+	var result plugin.PluginToValidationErrors
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	if assert.Len(t, result["demo"], 1) {
+		assert.Equal(t, "PluginMetadata.Commands[0].Usage", result["demo"][0].Namespace)
+		assert.Equal(t, plugin.PriorityError, result["demo"][0].Priority)
+	}
+}
+
+func TestRunSARIF(t *testing.T) {
+	binary := fakePluginWithMetadata(t, invalidMetadata())
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitIssues, run([]string{"-format", "sarif", binary}, &stdout, &stderr))
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	if assert.Len(t, log.Runs, 1) && assert.Len(t, log.Runs[0].Results, 1) {
+		result := log.Runs[0].Results[0]
+		assert.Equal(t, "PluginMetadata.Commands.Usage", result.RuleID)
+		assert.Equal(t, "error", result.Level)
+		assert.Equal(t, "Command 'list' has no usage information. Add usage text showing command syntax with parameters and options.", result.Message.Text)
+		assert.Equal(t, filepath.ToSlash(binary), result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, "demo.Commands[0].Usage", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+		assert.Equal(t, []sarifRule{{ID: "PluginMetadata.Commands.Usage", ShortDescription: sarifMessage{Text: "Invalid PluginMetadata.Commands.Usage"}}}, log.Runs[0].Tool.Driver.Rules)
+	}
+}
+
+func TestRunMinPriority(t *testing.T) {
+	metadata := validMetadata
+	// a lowercase argument in the usage is a warning
+	metadata.Commands = []plugin.Command{
+		{Namespace: "demo", Name: "show", Description: "Show a demo resource.", Usage: "ibmcloud demo show name"},
+	}
+	binary := fakePluginWithMetadata(t, metadata)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{binary}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "WARNING")
+	assert.Contains(t, stdout.String(), `Suggested value: "ibmcloud demo show NAME"`)
+
+	stdout.Reset()
+	assert.Equal(t, exitIssues, run([]string{"-min-priority", "warning", binary}, &stdout, &stderr))
+}
+
+func TestRunHandshakeResponse(t *testing.T) {
+	data, _ := json.Marshal(plugin.HandshakeResponse{ProtocolVersion: 1, Metadata: validMetadata})
+	binary := fakePlugin(t, string(data))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{binary}, &stdout, &stderr))
+}
+
+func TestRunFailures(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitFailure, run([]string{}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: plugin-metadata-validator")
+
+	binary := fakePluginWithMetadata(t, validMetadata)
+
+	stderr.Reset()
+	assert.Equal(t, exitFailure, run([]string{"-format", "xml", binary}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "invalid format 'xml'")
+
+	stderr.Reset()
+	assert.Equal(t, exitFailure, run([]string{"-min-priority", "FATAL", binary}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "invalid minimum priority 'FATAL'")
+
+	stderr.Reset()
+	assert.Equal(t, exitFailure, run([]string{fakePlugin(t, "not metadata")}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "unable to get the metadata of plugin")
+
+	stderr.Reset()
+	assert.Equal(t, exitFailure, run([]string{filepath.Join(t.TempDir(), "missing")}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "unable to get the metadata of plugin")
+}
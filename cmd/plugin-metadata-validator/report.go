@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin"
+)
+
+func writeText(w io.Writer, reports []pluginReport) error {
+	for _, r := range reports {
+		if len(r.Errors) == 0 {
+			fmt.Fprintf(w, "%s (%s): OK\n", r.Name, r.Binary)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s (%s): %d issue(s)\n", r.Name, r.Binary, len(r.Errors))
+		for _, e := range r.Errors {
+			location := e.Namespace
+			if e.CommandName != "" {
+				location += " [" + e.CommandName + "]"
+			}
+			fmt.Fprintf(w, "  %-7s %s: %s\n", e.Priority, location, e.Error)
+			if e.Remediation != "" && e.Remediation != e.Error {
+				fmt.Fprintf(w, "          %s\n", e.Remediation)
+			}
+			if e.Suggestion != nil && e.Suggestion.Value != "" {
+				fmt.Fprintf(w, "          Suggested value: %q\n", e.Suggestion.Value)
+			}
+			if e.Suggestion != nil && e.Suggestion.Hint != "" {
+				fmt.Fprintf(w, "          %s\n", e.Suggestion.Hint)
+			}
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, reports []pluginReport) error {
+	result := plugin.PluginToValidationErrors{}
+	for _, r := range reports {
+		result[r.Name] = r.Errors
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// SARIF 2.1.0 log, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+var sarifLevels = map[plugin.Priority]string{
+	plugin.PriorityError:   "error",
+	plugin.PriorityWarning: "warning",
+	plugin.PriorityInfo:    "note",
+}
+
+var indexRegexp = regexp.MustCompile(`\[\d+\]`)
+
+// sarifRuleID returns the rule of an error, the path of the offending field without the indexes
+func sarifRuleID(e plugin.PluginMetadataError) string {
+	if e.Namespace == "" {
+		return "PluginMetadata"
+	}
+	return indexRegexp.ReplaceAllString(e.Namespace, "")
+}
+
+func writeSARIF(w io.Writer, reports []pluginReport) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "plugin-metadata-validator",
+			InformationURI: "https://github.com/IBM-Cloud/ibm-cloud-cli-sdk",
+			Version:        bluemix.Version.String(),
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	rules := map[string]bool{}
+	for _, r := range reports {
+		for _, e := range r.Errors {
+			ruleID := sarifRuleID(e)
+			rules[ruleID] = true
+
+			level, ok := sarifLevels[e.Priority]
+			if !ok {
+				level = "error"
+			}
+			text := e.Error
+			if e.Remediation != "" && e.Remediation != e.Error {
+				text += " " + e.Remediation
+			}
+
+			name := r.Name + "." + strings.TrimPrefix(e.Namespace, "PluginMetadata.")
+			result := sarifResult{
+				RuleID:  ruleID,
+				Level:   level,
+				Message: sarifMessage{Text: text},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(r.Binary)}},
+					LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: name, Kind: "member"}},
+				}},
+			}
+			if e.CommandName != "" {
+				result.Properties = map[string]interface{}{"command": e.CommandName}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: "Invalid " + id}})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -2,6 +2,7 @@
 package downloader
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,10 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+
+	bhttp "github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/http"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
 )
 
 // ProxyReader is an interface to proxy read bytes
@@ -24,6 +29,17 @@ type FileDownloader struct {
 	DefaultHeader http.Header  // Default header to applied to the download request
 	Client        *http.Client // HTTP client to use, default is http DefaultClient
 	ProxyReader   ProxyReader
+	// TLSConfig is applied to a copy of the transport of the HTTP client, e.g. to trust additional
+	// CA certificates or to present a client certificate. See http.NewTLSConfig in package bluemix/http.
+	// If not set, the TLS settings of the environment variables are applied (see http.TLSOptionsFromEnv).
+	// A transport which is not an *http.Transport is used as is and must handle TLS on its own.
+	TLSConfig *tls.Config
+
+	mu sync.Mutex
+	// tlsClient is the client built by httpClient from tlsClientBase and tlsClientConfig
+	tlsClient       *http.Client
+	tlsClientBase   *http.Client
+	tlsClientConfig *tls.Config
 }
 
 // New creates a file downloader
@@ -49,9 +65,9 @@ func (d *FileDownloader) DownloadTo(url string, outputName string) (dest string,
 		return "", 0, fmt.Errorf("download request error: %v", err)
 	}
 
-	client := d.Client
-	if client == nil {
-		client = http.DefaultClient
+	client, err := d.httpClient()
+	if err != nil {
+		return "", 0, err
 	}
 
 	resp, err := client.Do(req)
@@ -105,6 +121,59 @@ func (d *FileDownloader) RemoveDir() error {
 	return os.RemoveAll(d.SaveDir)
 }
 
+// httpClient returns the HTTP client with the TLS config applied to a copy of its transport. The
+// client is built once and reused as long as Client and TLSConfig are unchanged.
+func (d *FileDownloader) httpClient() (*http.Client, error) {
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.tlsClient != nil && d.tlsClientBase == client && d.tlsClientConfig == d.TLSConfig {
+		return d.tlsClient, nil
+	}
+
+	tlsClient, err := newTLSClient(client, d.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	d.tlsClient, d.tlsClientBase, d.tlsClientConfig = tlsClient, client, d.TLSConfig
+	return tlsClient, nil
+}
+
+// newTLSClient returns a copy of the client whose transport uses the TLS config, or the TLS
+// settings of the environment variables if the TLS config is nil
+func newTLSClient(client *http.Client, tlsConfig *tls.Config) (*http.Client, error) {
+	if tlsConfig == nil {
+		opts := bhttp.TLSOptionsFromEnv()
+		if len(opts.CACertFiles) == 0 && opts.ClientCertFile == "" && opts.ClientKeyFile == "" {
+			return client, nil
+		}
+		var err error
+		if tlsConfig, err = bhttp.NewTLSConfig(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		trace.Logger.Printf("The TLS configuration is not applied to the download transport of type %T\n", rt)
+		return client, nil
+	}
+	transport = transport.Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	c := *client
+	c.Transport = transport
+	return &c, nil
+}
+
 func (d *FileDownloader) createRequest(url string) (*http.Request, error) {
 	r, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
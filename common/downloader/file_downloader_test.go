@@ -1,6 +1,9 @@
 package downloader
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -125,3 +128,86 @@ func CreateServerReturnContent(content string) *httptest.Server {
 		fmt.Fprint(w, "this is the file content")
 	}))
 }
+
+func (suite *DownloadTestSuite) TestDownload_TLSConfig() {
+	assert := assert.New(suite.T())
+
+	fileServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "123")
+	}))
+	defer fileServer.Close()
+
+	// the certificate of the server is not trusted
+	_, _, err := suite.downloader.DownloadTo(fileServer.URL, "untrusted.txt")
+	assert.Error(err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(fileServer.Certificate())
+	suite.downloader.TLSConfig = &tls.Config{RootCAs: pool}
+
+	dest, size, err := suite.downloader.DownloadTo(fileServer.URL, "trusted.txt")
+	assert.NoError(err)
+	assert.True(file_helpers.FileExists(dest))
+	assert.Equal(int64(3), size)
+	// the client of the downloader is left untouched
+	assert.Nil(suite.downloader.Client.Transport)
+}
+
+func (suite *DownloadTestSuite) TestDownload_TLSConfigFromEnv() {
+	assert := assert.New(suite.T())
+
+	fileServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "123")
+	}))
+	defer fileServer.Close()
+
+	caFile := filepath.Join(suite.T().TempDir(), "ca.pem")
+	assert.NoError(os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: fileServer.Certificate().Raw}), 0600))
+	suite.T().Setenv("IBMCLOUD_CA_CERT_FILE", caFile)
+
+	_, size, err := suite.downloader.DownloadTo(fileServer.URL, "trusted.txt")
+	assert.NoError(err)
+	assert.Equal(int64(3), size)
+}
+
+func (suite *DownloadTestSuite) TestDownload_TLSClientReused() {
+	assert := assert.New(suite.T())
+
+	suite.downloader.TLSConfig = &tls.Config{}
+	client, err := suite.downloader.httpClient()
+	assert.NoError(err)
+	again, err := suite.downloader.httpClient()
+	assert.NoError(err)
+	assert.Same(client, again)
+
+	// the client is rebuilt when the TLS config changes
+	suite.downloader.TLSConfig = &tls.Config{}
+	again, err = suite.downloader.httpClient()
+	assert.NoError(err)
+	assert.NotSame(client, again)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (suite *DownloadTestSuite) TestDownload_TLSConfigCustomTransport() {
+	assert := assert.New(suite.T())
+
+	fileServer := CreateServerReturnContent("123")
+	defer fileServer.Close()
+
+	// a transport which is not an *http.Transport is used as is
+	called := false
+	suite.downloader.Client = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	suite.downloader.TLSConfig = &tls.Config{}
+
+	_, _, err := suite.downloader.DownloadTo(fileServer.URL, "custom.txt")
+	assert.NoError(err)
+	assert.True(called)
+}
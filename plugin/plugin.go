@@ -98,6 +98,8 @@ type Command struct {
 	Flags       []Flag   `validate:"dive"`           // command options
 	Hidden      bool     // true to hide the command in help text
 	Stage       Stage    // stage of the command
+	ReplacedBy  string   // full qualified name of the command replacing a deprecated command, e.g. 'demo list'
+	RemovalDate string   // date when a deprecated command will be removed, e.g. '2027-01-31'
 }
 
 func (c Command) NameAndAliases() []string {
@@ -112,6 +114,15 @@ func (c Command) NameAndAliases() []string {
 // Method is used when defining the Flags in command metadata. @see Plugin#GetMetadata() for use case
 func ConvertCobraFlagsToPluginFlags(cmd *cobra.Command) []Flag {
 	var flags []Flag
+	visitCobraFlags(cmd, func(_ *pflag.Flag, flag Flag) {
+		flags = append(flags, flag)
+	})
+	return flags
+}
+
+// visitCobraFlags calls fn for each flag of the command, including the inherited ones, with the
+// flag converted to a Plugin Flag
+func visitCobraFlags(cmd *cobra.Command, fn func(f *pflag.Flag, flag Flag)) {
 	// NOTE: there is a strange behavior in Cobra where you need to call
 	// either `InheritedFlags` or `LocalFlags` in order to include global
 	// flags when calling `VisitAll`
@@ -129,16 +140,13 @@ func ConvertCobraFlagsToPluginFlags(cmd *cobra.Command) []Flag {
 		if f.Value.Type() == "bool" {
 			hasValue = false
 		}
-		flags = append(flags, Flag{
+		fn(f, Flag{
 			Name:        name,
 			Description: f.Usage,
 			HasValue:    hasValue,
 			Hidden:      f.Hidden,
 		})
 	})
-
-	return flags
-
 }
 
 // Flag describes a command option
@@ -198,6 +206,7 @@ type PluginContext interface {
 
 	// GetEndpoint is a utility method to return private or public endpoint for a requested service.
 	// It supports public cloud only. For non public clouds, plugin needs its own way to determine endpoint.
+	// Endpoints overridden by the user via `IBMCLOUD_ENDPOINT_<SERVICE>` or the endpoints overrides file take precedence.
 	GetEndpoint(endpoints.Service) (string, error)
 
 	// CloudName returns the name of the target cloud
@@ -306,3 +315,19 @@ type PluginContext interface {
 	// SSOType return the type of SSO used for the current session
 	SSOType() string
 }
+
+// TLSSettings provides the TLS settings of the CLI. It is implemented by the plugin context passed
+// to Run, but is not part of PluginContext so that existing implementations keep compiling.
+type TLSSettings interface {
+	// CACertFiles returns the PEM files of the CA certificates to trust in addition to the system ones.
+	// The environment variable 'IBMCLOUD_CA_CERT_FILE' takes precedence over the config.
+	CACertFiles() []string
+
+	// ClientCertFile returns the PEM file of the client certificate used for mutual TLS.
+	// The environment variable 'IBMCLOUD_CLIENT_CERT_FILE' takes precedence over the config.
+	ClientCertFile() string
+
+	// ClientKeyFile returns the PEM file of the private key of the client certificate.
+	// The environment variable 'IBMCLOUD_CLIENT_KEY_FILE' takes precedence over the config.
+	ClientKeyFile() string
+}
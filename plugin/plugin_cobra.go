@@ -0,0 +1,142 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ConvertCobraCommandToPluginMetadata walks the command tree of a plugin built with Cobra and
+// returns the metadata with its namespaces and commands. The root command and every command
+// having sub commands are namespaces, the other runnable commands are plugin commands. The other
+// fields, such as the version of the plugin, are taken from the given metadata; the name of the
+// plugin defaults to the name of the root command.
+//
+// Commands get their name, aliases, description and visibility from the Cobra command, the usage
+// from its command path, arguments and flags, followed by its examples. Deprecated Cobra commands
+// have the deprecated stage, without the stage warning as Cobra prints its own. Flags include the persistent flags inherited from the parents.
+func ConvertCobraCommandToPluginMetadata(root *cobra.Command, metadata PluginMetadata) PluginMetadata {
+	if metadata.Name == "" {
+		metadata.Name = root.Name()
+	}
+	metadata.IsCobraPlugin = true
+	metadata.Namespaces = nil
+	metadata.Commands = nil
+
+	var walk func(cmd *cobra.Command, parent string, hidden bool)
+	walk = func(cmd *cobra.Command, parent string, hidden bool) {
+		hidden = hidden || cmd.Hidden
+		if cmd.HasSubCommands() {
+			metadata.Namespaces = append(metadata.Namespaces, Namespace{
+				ParentName:  parent,
+				Name:        cmd.Name(),
+				Aliases:     cmd.Aliases,
+				Description: cobraDescription(cmd),
+				Stage:       cobraStage(cmd),
+			})
+			for _, sub := range cmd.Commands() {
+				// help and completion commands are provided by the CLI
+				if sub.Name() == "help" || sub.Name() == "completion" {
+					continue
+				}
+				walk(sub, qualifiedName(parent, cmd.Name()), hidden)
+			}
+			return
+		}
+
+		if !cmd.Runnable() {
+			return
+		}
+		flags, usage := cobraFlagsAndUsage(cmd)
+		metadata.Commands = append(metadata.Commands, Command{
+			Namespace:   parent,
+			Name:        cmd.Name(),
+			Aliases:     cmd.Aliases,
+			Description: cobraDescription(cmd),
+			Usage:       usage,
+			Flags:       flags,
+			Hidden:      hidden,
+			Stage:       cobraStage(cmd),
+		})
+	}
+	walk(root, "", false)
+
+	return metadata
+}
+
+func cobraDescription(cmd *cobra.Command) string {
+	if cmd.Short != "" {
+		return cmd.Short
+	}
+	return strings.TrimSpace(strings.SplitN(strings.TrimSpace(cmd.Long), "\n", 2)[0])
+}
+
+func cobraStage(cmd *cobra.Command) Stage {
+	if cmd.Deprecated != "" {
+		return StageDeprecated
+	}
+	return ""
+}
+
+// cobraFlagsAndUsage returns the flags of the command, like ConvertCobraFlagsToPluginFlags, and
+// its usage, e.g. 'ibmcloud demo show NAME [--output FORMAT]' followed by the examples of the
+// command. Both are built from the same walk of the flags.
+func cobraFlagsAndUsage(cmd *cobra.Command) ([]Flag, string) {
+	var flags []Flag
+	words := []string{"ibmcloud", cmd.CommandPath()}
+	// the first word of Use is the command name, Use may be empty though
+	if fields := strings.Fields(cmd.Use); len(fields) > 0 {
+		words = append(words, fields[1:]...)
+	}
+
+	visitCobraFlags(cmd, func(f *pflag.Flag, flag Flag) {
+		flags = append(flags, flag)
+		if flag.Hidden {
+			return
+		}
+		if !flag.HasValue {
+			words = append(words, "[--"+f.Name+"]")
+			return
+		}
+		valueName, _ := pflag.UnquoteUsage(f)
+		if valueName == "" {
+			valueName = "value"
+		}
+		words = append(words, "[--"+f.Name+" "+strings.ToUpper(valueName)+"]")
+	})
+
+	usage := strings.Join(strings.Fields(strings.Join(words, " ")), " ")
+	if cmd.Example != "" {
+		usage += "\n\nEXAMPLES:\n" + cmd.Example
+	}
+	return flags, usage
+}
+
+type pluginContextKey struct{}
+
+// RunCobraCommand runs the Cobra command tree of the plugin with the arguments of Plugin.Run. The
+// CLI runs the plugin with the command name and arguments only, so the namespace parsed by the
+// CLI is prepended to the arguments, without the root command. The plugin context can be
+// retrieved in the commands with CobraPluginContext.
+func RunCobraCommand(root *cobra.Command, pluginContext PluginContext, args []string) error {
+	path := strings.Fields(pluginContext.CommandNamespace())
+	if len(path) > 0 && path[0] == root.Name() {
+		path = path[1:]
+	} else if len(path) == 0 && len(args) > 0 && args[0] == root.Name() {
+		args = args[1:]
+	}
+
+	root.SetArgs(append(path, args...))
+	return root.ExecuteContext(context.WithValue(context.Background(), pluginContextKey{}, pluginContext))
+}
+
+// CobraPluginContext returns the plugin context of a command run by RunCobraCommand
+func CobraPluginContext(cmd *cobra.Command) (PluginContext, bool) {
+	if cmd.Context() == nil {
+		return nil, false
+	}
+	pluginContext, ok := cmd.Context().Value(pluginContextKey{}).(PluginContext)
+	return pluginContext, ok
+}
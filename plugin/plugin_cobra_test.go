@@ -0,0 +1,133 @@
+package plugin
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/terminal"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// namespaceContext is a plugin context returning the namespace parsed by the CLI
+type namespaceContext struct {
+	PluginContext
+	namespace string
+}
+
+func (c namespaceContext) CommandNamespace() string { return c.namespace }
+
+func newCobraTree(run func(cmd *cobra.Command, args []string)) *cobra.Command {
+	root := &cobra.Command{Use: "demo", Short: "Manage demo resources.", Aliases: []string{"dm"}}
+	root.PersistentFlags().StringP("region", "r", "", "Target `REGION`")
+
+	list := &cobra.Command{Use: "list", Short: "List all demo resources.", Aliases: []string{"ls"}, Run: run,
+		Example: "  ibmcloud demo list --output json"}
+	list.Flags().String("output", "", "Specify the output `FORMAT`")
+	list.Flags().BoolP("quiet", "q", false, "Suppress verbose output")
+
+	legacy := &cobra.Command{Use: "legacy", Long: "Manage legacy resources.\nThey are not supported anymore.", Deprecated: "use 'demo list' instead"}
+	migrate := &cobra.Command{Use: "migrate NAME", Short: "Migrate a legacy resource.", Run: run}
+	legacy.AddCommand(migrate)
+
+	secret := &cobra.Command{Use: "secret", Short: "Do something hidden.", Hidden: true, Run: run}
+	topic := &cobra.Command{Use: "topic", Short: "A help topic."}
+
+	root.AddCommand(list, legacy, secret, topic)
+	return root
+}
+
+func TestConvertCobraCommandToPluginMetadata(t *testing.T) {
+	metadata := ConvertCobraCommandToPluginMetadata(newCobraTree(func(*cobra.Command, []string) {}), PluginMetadata{Version: VersionType{Major: 1}, MinCliVersion: VersionType{Major: 2}})
+
+	assert.Equal(t, "demo", metadata.Name)
+	assert.Equal(t, VersionType{Major: 1}, metadata.Version)
+	assert.True(t, metadata.IsCobraPlugin)
+	assert.Equal(t, []Namespace{
+		{Name: "demo", Aliases: []string{"dm"}, Description: "Manage demo resources."},
+		{ParentName: "demo", Name: "legacy", Description: "Manage legacy resources.", Stage: StageDeprecated},
+	}, metadata.Namespaces)
+
+	if assert.Len(t, metadata.Commands, 3) {
+		// Cobra sorts the sub commands by name
+		migrate := metadata.Commands[0]
+		assert.Equal(t, "demo legacy", migrate.Namespace)
+		assert.Equal(t, "ibmcloud demo legacy migrate NAME [--region REGION]", migrate.Usage)
+
+		list := metadata.Commands[1]
+		assert.Equal(t, "demo", list.Namespace)
+		assert.Equal(t, "list", list.Name)
+		assert.Equal(t, []string{"ls"}, list.Aliases)
+		assert.Equal(t, "List all demo resources.", list.Description)
+		assert.Equal(t, "ibmcloud demo list [--output FORMAT] [--quiet] [--region REGION]\n\nEXAMPLES:\n  ibmcloud demo list --output json", list.Usage)
+		assert.Equal(t, []Flag{
+			{Name: "output", Description: "Specify the output `FORMAT`", HasValue: true},
+			{Name: "q,quiet", Description: "Suppress verbose output"},
+			{Name: "r,region", Description: "Target `REGION`", HasValue: true},
+		}, list.Flags)
+
+		secret := metadata.Commands[2]
+		assert.Equal(t, "secret", secret.Name)
+		assert.True(t, secret.Hidden)
+	}
+
+	errs := NewPluginMetadataValidator().Errors([]PluginMetadata{metadata})
+	for _, e := range errs["demo"] {
+		assert.NotEqual(t, PriorityError, e.Priority, e.Error)
+	}
+}
+
+func TestRunCobraCommand(t *testing.T) {
+	var (
+		ran     string
+		gotArgs []string
+		gotCtx  PluginContext
+	)
+	root := newCobraTree(func(cmd *cobra.Command, args []string) {
+		ran = cmd.Name()
+		gotArgs = args
+		gotCtx, _ = CobraPluginContext(cmd)
+	})
+
+	context := namespaceContext{namespace: "demo legacy"}
+	assert.NoError(t, RunCobraCommand(root, context, []string{"migrate", "NAME"}))
+	assert.Equal(t, "migrate", ran)
+	assert.Equal(t, []string{"NAME"}, gotArgs)
+	assert.Equal(t, context, gotCtx)
+
+	assert.NoError(t, RunCobraCommand(root, namespaceContext{}, []string{"demo", "ls", "-r", "us-south"}))
+	assert.Equal(t, "list", ran)
+	assert.Empty(t, gotArgs)
+
+	_, ok := CobraPluginContext(&cobra.Command{})
+	assert.False(t, ok)
+}
+
+func TestConvertCobraCommandWithoutUse(t *testing.T) {
+	root := &cobra.Command{Use: "demo"}
+	root.AddCommand(&cobra.Command{Short: "Do something.", Run: func(*cobra.Command, []string) {}})
+
+	metadata := ConvertCobraCommandToPluginMetadata(root, PluginMetadata{})
+	if assert.Len(t, metadata.Commands, 1) {
+		assert.Equal(t, "ibmcloud demo", metadata.Commands[0].Usage)
+	}
+}
+
+func TestRunDeprecatedCobraCommandWarnsOnce(t *testing.T) {
+	t.Setenv("IBMCLOUD_HOME", t.TempDir())
+	t.Setenv("IBMCLOUD_CLI", "ibmcloud")
+	t.Setenv("IBMCLOUD_PLUGIN_NAMESPACE", "demo")
+	context := InitPluginContext("demo")
+
+	root := newCobraTree(func(*cobra.Command, []string) {})
+	root.AddCommand(&cobra.Command{Use: "old", Short: "Do something old.", Deprecated: "use 'demo list' instead", Run: func(*cobra.Command, []string) {}})
+	metadata := ConvertCobraCommandToPluginMetadata(root, PluginMetadata{})
+
+	var errOut bytes.Buffer
+	root.SetOut(&errOut)
+	root.SetErr(&errOut)
+	warnCommandStage(terminal.NewUI(&bytes.Buffer{}, &bytes.Buffer{}, &errOut), context, metadata, []string{"old"})
+	assert.NoError(t, RunCobraCommand(root, context, []string{"old"}))
+	assert.Equal(t, 1, strings.Count(errOut.String(), "is deprecated"), errOut.String())
+}
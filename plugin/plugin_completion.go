@@ -0,0 +1,254 @@
+package plugin
+
+import (
+	"sort"
+	"strings"
+)
+
+// CompletionRequest is a request to complete the word under the cursor of a command line of
+// the plugin. The CLI sends it by running the plugin with the arguments 'SendCompletion <args>',
+// where args are the words following the CLI name and the last one is the word to complete.
+type CompletionRequest struct {
+	// Namespace is the full qualified name of the namespace of the command line
+	Namespace string
+	// Command is the command of the command line, nil if the command line is a namespace
+	Command *Command
+	// Args are the positional arguments of the command before the word to complete
+	Args []string
+	// Flag is the flag whose value is completed, nil if the word to complete is not a flag value
+	Flag *Flag
+	// ToComplete is the partial word to complete, possibly empty
+	ToComplete string
+}
+
+// Completer can be implemented by a Plugin to complete the arguments and flag values of its
+// commands dynamically, e.g. with the names of resources. The returned candidates are filtered by
+// the word to complete.
+type Completer interface {
+	Complete(context PluginContext, req CompletionRequest) []string
+}
+
+// NewCompletionRequest parses the words of a command line following the CLI name, the last one
+// being the word to complete
+func NewCompletionRequest(metadata PluginMetadata, args []string) CompletionRequest {
+	req := CompletionRequest{}
+	if len(args) == 0 {
+		return req
+	}
+	req.ToComplete = args[len(args)-1]
+
+	tree := newCompletionTree(metadata)
+	var pendingFlag *Flag
+	for _, word := range args[:len(args)-1] {
+		switch {
+		case pendingFlag != nil:
+			pendingFlag = nil
+		case strings.HasPrefix(word, "-"):
+			if req.Command != nil && !strings.Contains(word, "=") {
+				if f := findFlag(req.Command, word); f != nil && f.HasValue {
+					pendingFlag = f
+				}
+			}
+		case req.Command != nil:
+			req.Args = append(req.Args, word)
+		default:
+			if ns, ok := tree.namespace(req.Namespace, word); ok {
+				req.Namespace = ns
+			} else if cmd := tree.command(req.Namespace, word); cmd != nil {
+				req.Command = cmd
+			} else {
+				req.Args = append(req.Args, word)
+			}
+		}
+	}
+	req.Flag = pendingFlag
+	return req
+}
+
+// StaticCompletions returns the namespaces, commands and flags completing the request
+func StaticCompletions(metadata PluginMetadata, req CompletionRequest) []string {
+	var candidates []string
+	switch {
+	case req.Flag != nil:
+		// flag values can only be completed by the plugin
+	case req.Command != nil:
+		if strings.HasPrefix(req.ToComplete, "-") {
+			candidates = flagCandidates(req.Command)
+		}
+	default:
+		candidates = newCompletionTree(metadata).children(req.Namespace)
+	}
+	return filterCompletions(candidates, req.ToComplete)
+}
+
+func completeCommand(plugin Plugin, args []string) []string {
+	metadata := plugin.GetMetadata()
+	req := NewCompletionRequest(metadata, args)
+	candidates := StaticCompletions(metadata, req)
+
+	completer, ok := plugin.(Completer)
+	if ok && req.Command != nil && (req.Flag != nil || !strings.HasPrefix(req.ToComplete, "-")) {
+		context := InitPluginContext(metadata.Name)
+		candidates = append(candidates, filterCompletions(completer.Complete(context, req), req.ToComplete)...)
+	}
+	return candidates
+}
+
+func isCompletionRequest(args []string) bool {
+	return len(args) >= 1 && args[0] == "SendCompletion"
+}
+
+func findFlag(cmd *Command, word string) *Flag {
+	name := strings.TrimLeft(word, "-")
+	for i, f := range cmd.Flags {
+		for _, n := range strings.Split(f.Name, ",") {
+			if strings.TrimSpace(n) == name {
+				return &cmd.Flags[i]
+			}
+		}
+	}
+	return nil
+}
+
+// flagCandidates returns the names of the visible flags of the command, e.g. '--name' and '-n'
+func flagCandidates(cmd *Command) []string {
+	var candidates []string
+	for _, f := range cmd.Flags {
+		if f.Hidden {
+			continue
+		}
+		for _, n := range strings.Split(f.Name, ",") {
+			n = strings.TrimSpace(n)
+			if len(n) == 1 {
+				candidates = append(candidates, "-"+n)
+			} else {
+				candidates = append(candidates, "--"+n)
+			}
+		}
+	}
+	return candidates
+}
+
+func filterCompletions(candidates []string, prefix string) []string {
+	ret := []string{}
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// completionTree indexes the namespaces and commands of the plugin by their parent namespace.
+// Namespaces which are not defined by the plugin, such as shared namespaces, are derived from
+// the namespaces of the commands.
+type completionTree struct {
+	namespaces map[string][]Namespace // by full qualified name of the parent
+	commands   map[string][]*Command  // by full qualified name of the namespace
+}
+
+func newCompletionTree(metadata PluginMetadata) completionTree {
+	t := completionTree{
+		namespaces: map[string][]Namespace{},
+		commands:   map[string][]*Command{},
+	}
+
+	defined := map[string]bool{}
+	for _, ns := range metadata.Namespaces {
+		full := qualifiedName(ns.ParentName, ns.Name)
+		if !defined[full] {
+			defined[full] = true
+			t.namespaces[ns.ParentName] = append(t.namespaces[ns.ParentName], ns)
+		}
+	}
+	for i := range metadata.Commands {
+		cmd := &metadata.Commands[i]
+		t.commands[cmd.Namespace] = append(t.commands[cmd.Namespace], cmd)
+
+		words := strings.Fields(cmd.Namespace)
+		for j := range words {
+			parent, full := strings.Join(words[:j], " "), strings.Join(words[:j+1], " ")
+			if !defined[full] {
+				defined[full] = true
+				t.namespaces[parent] = append(t.namespaces[parent], Namespace{ParentName: parent, Name: words[j]})
+			}
+		}
+	}
+	return t
+}
+
+func qualifiedName(parent, name string) string {
+	return strings.TrimSpace(parent + " " + name)
+}
+
+// namespace returns the full qualified name of the sub namespace of the given name or alias
+func (t completionTree) namespace(parent string, word string) (string, bool) {
+	for _, ns := range t.namespaces[parent] {
+		for _, n := range ns.NameAndAliases() {
+			if n == word {
+				return qualifiedName(parent, ns.Name), true
+			}
+		}
+	}
+	return "", false
+}
+
+// command returns the command of the namespace of the given name or alias
+func (t completionTree) command(namespace string, word string) *Command {
+	for _, cmd := range t.commands[namespace] {
+		for _, n := range cmd.NameAndAliases() {
+			if n == word {
+				return cmd
+			}
+		}
+	}
+	return nil
+}
+
+// children returns the names of the sub namespaces and visible commands of the namespace
+func (t completionTree) children(namespace string) []string {
+	var names []string
+	for _, ns := range t.namespaces[namespace] {
+		names = append(names, ns.Name)
+	}
+	for _, cmd := range t.commands[namespace] {
+		if !cmd.Hidden {
+			names = append(names, cmd.Name)
+		}
+	}
+	return names
+}
+
+// paths returns the candidates of each prefix of a command line, including the aliases, keyed by
+// the words of the prefix joined by a space. The root is keyed by the CLI name.
+func (t completionTree) paths(cliName string) map[string][]string {
+	ret := map[string][]string{}
+	var walk func(prefix string, namespace string)
+	walk = func(prefix string, namespace string) {
+		ret[prefix] = t.children(namespace)
+		for _, ns := range t.namespaces[namespace] {
+			for _, n := range ns.NameAndAliases() {
+				walk(prefix+" "+n, qualifiedName(namespace, ns.Name))
+			}
+		}
+		for _, cmd := range t.commands[namespace] {
+			if cmd.Hidden {
+				continue
+			}
+			for _, n := range cmd.NameAndAliases() {
+				ret[prefix+" "+n] = flagCandidates(cmd)
+			}
+		}
+	}
+	walk(cliName, "")
+	return ret
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
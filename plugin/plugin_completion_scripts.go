@@ -0,0 +1,306 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Shell is a shell supported by the completion scripts
+type Shell string
+
+// Supported shells
+const (
+	ShellBash       Shell = "bash"
+	ShellZsh        Shell = "zsh"
+	ShellFish       Shell = "fish"
+	ShellPowerShell Shell = "powershell"
+)
+
+// CompletionOptions configures the generated completion scripts
+type CompletionOptions struct {
+	// CLIName is the name of the CLI binary to complete, default is 'ibmcloud'
+	CLIName string
+	// Binary is the path of the plugin binary. If set, the script delegates the completion to the
+	// binary with the 'SendCompletion' arguments, so that arguments and flag values are also
+	// completed by the Completer of the plugin, which the binary must implement. Otherwise the script completes the namespaces,
+	// commands and flags of the metadata only.
+	Binary string
+}
+
+func (o CompletionOptions) cliName() string {
+	if o.CLIName == "" {
+		return "ibmcloud"
+	}
+	return o.CLIName
+}
+
+var nonIdentifierRegexp = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// GenerateCompletionScript writes the completion script of the plugin commands for the shell.
+// The script only completes the command lines whose first word is a namespace or command of the
+// plugin, and the first word itself. The other command lines are completed by the completion
+// registered for the CLI before the script is loaded, if any, so that the completion of the CLI
+// and of the other plugins keeps working.
+func GenerateCompletionScript(w io.Writer, metadata PluginMetadata, shell Shell, opts CompletionOptions) error {
+	cli := opts.cliName()
+	fn := nonIdentifierRegexp.ReplaceAllString("_"+cli+"_"+metadata.Name+"_complete", "_")
+	paths := newCompletionTree(metadata).paths(cli)
+	header := fmt.Sprintf("%s completion of the %s plug-in %s %s, generated from its metadata", shell, cli, metadata.Name, metadata.Version)
+
+	var script string
+	switch shell {
+	case ShellBash:
+		script = bashCompletionScript(header, fn, cli, paths, opts.Binary)
+	case ShellZsh:
+		script = zshCompletionScript(header, fn, cli, paths, opts.Binary)
+	case ShellFish:
+		script = fishCompletionScript(header, fn, cli, paths, opts.Binary)
+	case ShellPowerShell:
+		script = powerShellCompletionScript(header, fn, cli, paths, opts.Binary)
+	default:
+		return fmt.Errorf("unsupported shell '%s', supported shells are %s, %s, %s and %s", shell, ShellBash, ShellZsh, ShellFish, ShellPowerShell)
+	}
+
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// ownedWords returns the names and aliases of the top-level namespaces and commands of the plugin,
+// which are the first words of the command lines completed by the script
+func ownedWords(cli string, paths map[string][]string) []string {
+	var words []string
+	for _, key := range sortedKeys(paths) {
+		if word, ok := strings.CutPrefix(key, cli+" "); ok && !strings.Contains(word, " ") {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// posixQuote quotes the string for bash and zsh
+func posixQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func bashCompletionScript(header, fn, cli string, paths map[string][]string, binary string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", header)
+	// the completion previously registered for the CLI completes the command lines of other commands
+	fmt.Fprintf(&b, "if [[ -z \"${%s_previous+set}\" ]]; then\n", fn)
+	fmt.Fprintf(&b, "    %s_previous=$(complete -p %s 2>/dev/null | sed -n 's/.*-F \\([^ ]*\\) .*/\\1/p')\n", fn, posixQuote(cli))
+	fmt.Fprintf(&b, "    [[ \"$%s_previous\" == %s ]] && %s_previous=\nfi\n\n", fn, fn, fn)
+
+	if binary == "" {
+		// a case statement rather than an associative array, which bash 3 of macOS does not support
+		fmt.Fprintf(&b, "%s_candidates() {\n    case \"$1\" in\n", fn)
+		for _, key := range sortedKeys(paths) {
+			fmt.Fprintf(&b, "        %s) echo %s ;;\n", posixQuote(key), posixQuote(strings.Join(paths[key], " ")))
+		}
+		b.WriteString("        *) return 1 ;;\n    esac\n}\n\n")
+	}
+
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	fmt.Fprintf(&b, "    local owned=%s\n", posixQuote(" "+strings.Join(ownedWords(cli, paths), " ")+" "))
+	fmt.Fprintf(&b, "    local -a previous=()\n")
+	fmt.Fprintf(&b, `    if [[ -n "$%s_previous" ]] && [[ $COMP_CWORD -le 1 || "$owned" != *" ${COMP_WORDS[1]} "* ]]; then
+        "$%s_previous" "$@"
+        previous=("${COMPREPLY[@]}")
+    fi
+    COMPREPLY=()
+    if [[ $COMP_CWORD -gt 1 && "$owned" != *" ${COMP_WORDS[1]} "* ]]; then
+        COMPREPLY=("${previous[@]}")
+        return
+    fi
+`, fn, fn)
+	if binary != "" {
+		fmt.Fprintf(&b, "    local IFS=$'\\n'\n")
+		fmt.Fprintf(&b, "    COMPREPLY=($(%s SendCompletion \"${COMP_WORDS[@]:1:COMP_CWORD}\" 2>/dev/null))\n", posixQuote(binary))
+	} else {
+		fmt.Fprintf(&b, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prefix=%s key word i\n", posixQuote(cli))
+		fmt.Fprintf(&b, `    for ((i = 1; i < COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+        [[ "$word" == -* ]] && continue
+        key="$prefix $word"
+        %s_candidates "$key" >/dev/null && prefix="$key"
+    done
+    COMPREPLY=($(compgen -W "$(%s_candidates "$prefix")" -- "$cur"))
+`, fn, fn)
+	}
+	b.WriteString("    COMPREPLY=(\"${previous[@]}\" \"${COMPREPLY[@]}\")\n")
+	fmt.Fprintf(&b, "}\n\ncomplete -F %s %s\n", fn, posixQuote(cli))
+	return b.String()
+}
+
+func zshCompletionScript(header, fn, cli string, paths map[string][]string, binary string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", header)
+	// the completion previously registered for the CLI completes the command lines of other commands
+	fmt.Fprintf(&b, "(( ${+%s_previous} )) || typeset -g %s_previous=${_comps[%s]}\n", fn, fn, cli)
+	fmt.Fprintf(&b, "[[ $%s_previous == %s ]] && %s_previous=\n\n", fn, fn, fn)
+
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	fmt.Fprintf(&b, "    local owned=%s\n", posixQuote(" "+strings.Join(ownedWords(cli, paths), " ")+" "))
+	fmt.Fprintf(&b, `    if [[ -n $%s_previous ]] && { (( CURRENT <= 2 )) || [[ $owned != *" ${words[2]} "* ]] }; then
+        $%s_previous "$@"
+    fi
+    (( CURRENT > 2 )) && [[ $owned != *" ${words[2]} "* ]] && return
+`, fn, fn)
+	if binary != "" {
+		b.WriteString("    local -a reply\n")
+		fmt.Fprintf(&b, "    reply=(\"${(@f)$(%s SendCompletion \"${(@)words[2,CURRENT]}\" 2>/dev/null)}\")\n", posixQuote(binary))
+		b.WriteString("    compadd -- ${reply:#}\n")
+	} else {
+		b.WriteString("    local -A candidates\n    candidates=(\n")
+		for _, key := range sortedKeys(paths) {
+			fmt.Fprintf(&b, "        %s %s\n", posixQuote(key), posixQuote(strings.Join(paths[key], " ")))
+		}
+		b.WriteString("    )\n")
+		fmt.Fprintf(&b, "    local prefix=%s key word i\n", posixQuote(cli))
+		b.WriteString(`    for ((i = 2; i < CURRENT; i++)); do
+        word=${words[i]}
+        [[ $word == -* ]] && continue
+        key="$prefix $word"
+        (( ${+candidates[$key]} )) && prefix=$key
+    done
+    compadd -- ${=candidates[$prefix]}
+`)
+	}
+	fmt.Fprintf(&b, "}\n\ncompdef %s %s\n", fn, posixQuote(cli))
+	return b.String()
+}
+
+func fishCompletionScript(header, fn, cli string, paths map[string][]string, binary string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", header)
+
+	// fish combines the completions of a command, the other ones complete the other command lines
+	var owned []string
+	for _, w := range ownedWords(cli, paths) {
+		owned = append(owned, fishQuote(w))
+	}
+	fmt.Fprintf(&b, `function %s_applies
+    set -l words (commandline -opc)
+    test (count $words) -le 1; and return 0
+    contains -- $words[2] %s
+end
+
+`, fn, strings.Join(owned, " "))
+
+	if binary != "" {
+		fmt.Fprintf(&b, "complete -c %s -f -n %s_applies -a '(%s SendCompletion (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'\n",
+			fishQuote(cli), fn, strings.ReplaceAll(fishQuote(binary), "'", `\'`))
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "function %s_candidates\n    switch $argv[1]\n", fn)
+	for _, key := range sortedKeys(paths) {
+		fmt.Fprintf(&b, "        case %s\n", fishQuote(key))
+		if len(paths[key]) > 0 {
+			quoted := make([]string, len(paths[key]))
+			for i, c := range paths[key] {
+				quoted[i] = fishQuote(c)
+			}
+			fmt.Fprintf(&b, "            printf '%%s\\n' %s\n", strings.Join(quoted, " "))
+		} else {
+			b.WriteString("            true\n")
+		}
+	}
+	b.WriteString("        case '*'\n            return 1\n    end\nend\n\n")
+
+	fmt.Fprintf(&b, "function %s\n    set -l prefix %s\n", fn, fishQuote(cli))
+	fmt.Fprintf(&b, `    for word in (commandline -opc)[2..-1]
+        string match -q -- '-*' $word; and continue
+        if %s_candidates "$prefix $word" >/dev/null
+            set prefix "$prefix $word"
+        end
+    end
+    %s_candidates $prefix
+end
+
+`, fn, fn)
+	fmt.Fprintf(&b, "complete -c %s -f -n %s_applies -a '(%s)'\n", fishQuote(cli), fn, fn)
+	return b.String()
+}
+
+func powerShellCompletionScript(header, fn, cli string, paths map[string][]string, binary string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", header)
+
+	// the completer previously registered for the CLI completes the command lines of other
+	// commands. PowerShell does not expose the registered completers, they are read once from its
+	// internal state, which differs between versions; the lookup checks every step and the
+	// completion falls through to the plugin commands only if any of them is missing.
+	fmt.Fprintf(&b, "if (-not (Test-Path variable:global:%s_previous)) {\n", fn)
+	fmt.Fprintf(&b, "    $global:%s_previous = $null\n", fn)
+	fmt.Fprintf(&b, `    try {
+        $field = $ExecutionContext.GetType().GetField('_context', 'NonPublic,Instance')
+        $context = if ($field) { $field.GetValue($ExecutionContext) }
+        $property = if ($context) { $context.GetType().GetProperty('NativeArgumentCompleters', 'NonPublic,Instance') }
+        $completers = if ($property) { $property.GetValue($context) }
+        if ($completers -and $completers[%s] -is [scriptblock]) { $global:%s_previous = $completers[%s] }
+    } catch {
+        $global:%s_previous = $null
+    }
+}
+
+`, powerShellQuote(cli), fn, powerShellQuote(cli), fn)
+
+	var owned []string
+	for _, w := range ownedWords(cli, paths) {
+		owned = append(owned, powerShellQuote(w))
+	}
+	fmt.Fprintf(&b, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", powerShellQuote(cli))
+	b.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n")
+	fmt.Fprintf(&b, "    $owned = @(%s)\n", strings.Join(owned, ", "))
+	fmt.Fprintf(&b, `    $first = $commandAst.CommandElements | Select-Object -Skip 1 -First 1 |
+        Where-Object { $_.Extent.EndOffset -lt $cursorPosition } | ForEach-Object { $_.ToString() }
+    $previous = $global:%s_previous
+    if ($previous -and (-not $first -or $owned -notcontains $first)) {
+        try { & $previous $wordToComplete $commandAst $cursorPosition } catch {}
+    }
+    if ($first -and $owned -notcontains $first) { return }
+`, fn)
+	if binary != "" {
+		b.WriteString(`    $words = @($commandAst.CommandElements | Select-Object -Skip 1 |
+        Where-Object { $_.Extent.EndOffset -lt $cursorPosition } | ForEach-Object { $_.ToString() })
+`)
+		fmt.Fprintf(&b, "    $candidates = & %s SendCompletion @words $wordToComplete 2>$null\n", powerShellQuote(binary))
+	} else {
+		b.WriteString("    $paths = @{\n")
+		for _, key := range sortedKeys(paths) {
+			quoted := make([]string, len(paths[key]))
+			for i, c := range paths[key] {
+				quoted[i] = powerShellQuote(c)
+			}
+			fmt.Fprintf(&b, "        %s = @(%s)\n", powerShellQuote(key), strings.Join(quoted, ", "))
+		}
+		b.WriteString("    }\n")
+		fmt.Fprintf(&b, "    $prefix = %s\n", powerShellQuote(cli))
+		b.WriteString(`    foreach ($element in ($commandAst.CommandElements | Select-Object -Skip 1)) {
+        if ($element.Extent.EndOffset -ge $cursorPosition) { break }
+        $word = $element.ToString()
+        if ($word.StartsWith('-')) { continue }
+        $key = "$prefix $word"
+        if ($paths.ContainsKey($key)) { $prefix = $key }
+    }
+    $candidates = $paths[$prefix] | Where-Object { $_ -like "$wordToComplete*" }
+`)
+	}
+	b.WriteString(`    $candidates | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`)
+	return b.String()
+}
@@ -0,0 +1,200 @@
+package plugin
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+var completionMetadata = PluginMetadata{
+	Name:    "demo",
+	Version: VersionType{Major: 1, Minor: 0, Build: 0},
+	Namespaces: []Namespace{
+		{Name: "demo", Aliases: []string{"dm"}},
+		{ParentName: "demo", Name: "legacy"},
+	},
+	Commands: []Command{
+		{
+			Namespace: "demo",
+			Name:      "list",
+			Aliases:   []string{"ls"},
+			Flags: []Flag{
+				{Name: "output", HasValue: true},
+				{Name: "q,quiet"},
+				{Name: "debug", Hidden: true},
+			},
+		},
+		{Namespace: "demo", Name: "show"},
+		{Namespace: "demo", Name: "secret", Hidden: true},
+		{Namespace: "demo legacy", Name: "migrate"},
+		{Namespace: "resource", Name: "demo-instances"},
+	},
+}
+
+type completerTestPlugin struct {
+	requests []CompletionRequest
+}
+
+func (p *completerTestPlugin) GetMetadata() PluginMetadata              { return completionMetadata }
+func (p *completerTestPlugin) Run(context PluginContext, args []string) {}
+func (p *completerTestPlugin) Complete(context PluginContext, req CompletionRequest) []string {
+	p.requests = append(p.requests, req)
+	if req.Flag != nil {
+		return []string{"json", "yaml"}
+	}
+	return []string{"instance-a", "instance-b", "other"}
+}
+
+func TestNewCompletionRequest(t *testing.T) {
+	req := NewCompletionRequest(completionMetadata, []string{"dm", "ls", "--output", "json", "NAME", "in"})
+	assert.Equal(t, "demo", req.Namespace)
+	assert.Equal(t, "list", req.Command.Name)
+	assert.Equal(t, []string{"NAME"}, req.Args)
+	assert.Nil(t, req.Flag)
+	assert.Equal(t, "in", req.ToComplete)
+
+	req = NewCompletionRequest(completionMetadata, []string{"demo", "list", "--output", ""})
+	assert.Equal(t, "output", req.Flag.Name)
+
+	req = NewCompletionRequest(completionMetadata, []string{"demo", "legacy", ""})
+	assert.Equal(t, "demo legacy", req.Namespace)
+	assert.Nil(t, req.Command)
+
+	req = NewCompletionRequest(completionMetadata, nil)
+	assert.Equal(t, CompletionRequest{}, req)
+}
+
+func TestStaticCompletions(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{""}, []string{"demo", "resource"}},
+		{[]string{"demo", ""}, []string{"legacy", "list", "show"}},
+		{[]string{"dm", "l"}, []string{"legacy", "list"}},
+		{[]string{"demo", "legacy", ""}, []string{"migrate"}},
+		{[]string{"resource", ""}, []string{"demo-instances"}},
+		{[]string{"demo", "list", "-"}, []string{"--output", "-q", "--quiet"}},
+		{[]string{"demo", "list", "--q"}, []string{"--quiet"}},
+		{[]string{"demo", "list", ""}, []string{}},
+		{[]string{"demo", "list", "--output", ""}, []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			req := NewCompletionRequest(completionMetadata, tc.args)
+			assert.Equal(t, tc.expected, StaticCompletions(completionMetadata, req))
+		})
+	}
+}
+
+func TestStartWithArgsCompletion(t *testing.T) {
+	t.Setenv("IBMCLOUD_HOME", t.TempDir())
+
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"SendCompletion", "demo", "s"}, "show\n"},
+		{[]string{"SendCompletion", "demo", "list", "inst"}, "instance-a\ninstance-b\n"},
+		{[]string{"SendCompletion", "demo", "list", "--output", ""}, "json\nyaml\n"},
+		{[]string{"SendCompletion", "demo", "list", "--q"}, "--quiet\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			orgStdout := os.Stdout
+			stdoutMock := testhelpers.CreateMockStdout()
+			defer func() {
+				os.Stdout = orgStdout
+				os.RemoveAll(stdoutMock.File.Name())
+				stdoutMock.File.Close()
+			}()
+			os.Stdout = stdoutMock.File
+
+			StartWithArgs(&completerTestPlugin{}, tc.args)
+			assert.Equal(t, tc.expected, stdoutMock.Read())
+		})
+	}
+}
+
+func TestGenerateCompletionScriptUnsupportedShell(t *testing.T) {
+	err := GenerateCompletionScript(&bytes.Buffer{}, completionMetadata, Shell("tcsh"), CompletionOptions{})
+	assert.Error(t, err)
+}
+
+func TestGenerateCompletionScripts(t *testing.T) {
+	for _, shell := range []Shell{ShellBash, ShellZsh, ShellFish, ShellPowerShell} {
+		t.Run(string(shell), func(t *testing.T) {
+			var static bytes.Buffer
+			assert.NoError(t, GenerateCompletionScript(&static, completionMetadata, shell, CompletionOptions{}))
+			assert.Contains(t, static.String(), "'ibmcloud dm ls'")
+			assert.NotContains(t, static.String(), "secret")
+			assert.NotContains(t, static.String(), "SendCompletion")
+
+			var dynamic bytes.Buffer
+			assert.NoError(t, GenerateCompletionScript(&dynamic, completionMetadata, shell, CompletionOptions{CLIName: "ic", Binary: "/opt/plugins/demo"}))
+			assert.Contains(t, dynamic.String(), "/opt/plugins/demo")
+			assert.Contains(t, dynamic.String(), "SendCompletion")
+		})
+	}
+}
+
+func TestBashCompletionScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+
+	var script bytes.Buffer
+	assert.NoError(t, GenerateCompletionScript(&script, completionMetadata, ShellBash, CompletionOptions{}))
+	// bash 3 of macOS has no associative arrays
+	assert.NotContains(t, script.String(), "local -A")
+
+	complete := func(previous string, line string) string {
+		words := strings.Split(line, " ")
+		cmd := exec.Command("bash", "-c", previous+script.String()+`
+COMP_WORDS=("$@")
+COMP_CWORD=$(($# - 1))
+_ibmcloud_demo_complete
+echo "${COMPREPLY[*]}"`, "bash")
+		cmd.Args = append(cmd.Args, words...)
+		out, err := cmd.Output()
+		assert.NoError(t, err)
+		return strings.TrimSpace(string(out))
+	}
+
+	assert.Equal(t, "demo resource", complete("", "ibmcloud "))
+	assert.Equal(t, "legacy list", complete("", "ibmcloud dm l"))
+	assert.Equal(t, "migrate", complete("", "ibmcloud demo legacy "))
+	assert.Equal(t, "--output -q --quiet", complete("", "ibmcloud demo ls -"))
+	assert.Equal(t, "--output -q --quiet", complete("", "ibmcloud demo list --quiet NAME -"))
+	assert.Equal(t, "", complete("", "ibmcloud target -"))
+
+	// the command lines of other commands are completed by the completion registered for the CLI
+	previous := `_ibmcloud_cli() { COMPREPLY=(cli-${COMP_CWORD}); }
+complete -F _ibmcloud_cli ibmcloud
+`
+	assert.Equal(t, "cli-1 demo resource", complete(previous, "ibmcloud "))
+	assert.Equal(t, "cli-2", complete(previous, "ibmcloud target -"))
+	assert.Equal(t, "list", complete(previous, "ibmcloud dm lis"))
+}
+
+type runTestPlugin struct {
+	args []string
+}
+
+func (p *runTestPlugin) GetMetadata() PluginMetadata              { return completionMetadata }
+func (p *runTestPlugin) Run(context PluginContext, args []string) { p.args = args }
+
+func TestStartWithArgsCompletionWithoutCompleter(t *testing.T) {
+	t.Setenv("IBMCLOUD_HOME", t.TempDir())
+
+	p := &runTestPlugin{}
+	StartWithArgs(p, []string{"SendCompletion", "demo", "s"})
+	assert.Equal(t, []string{"SendCompletion", "demo", "s"}, p.args)
+}
@@ -42,8 +42,16 @@ func (c *pluginContext) ConsoleEndpoint() string {
 	return c.ConsoleEndpoints().PublicEndpoint
 }
 
-// GetEndpoint returns the private or public endpoint for a requested service
+// GetEndpoint returns the private or public endpoint for a requested service.
+// An endpoint overridden by the user through the environment or the endpoints
+// overrides file takes precedence.
 func (c *pluginContext) GetEndpoint(svc endpoints.Service) (string, error) {
+	if endpoint, _, found, err := endpoints.DefaultRegistry().Override(svc); err != nil {
+		return "", err
+	} else if found {
+		return endpoint, nil
+	}
+
 	if c.CloudType() != "public" {
 		return "", fmt.Errorf("only public cloud is supported")
 	}
@@ -112,6 +120,21 @@ func (c *pluginContext) ColorEnabled() string {
 	return envOrConfig(bluemix.EnvColor, c.ReadWriter.ColorEnabled())
 }
 
+func (c *pluginContext) CACertFiles() []string {
+	if v := bluemix.EnvCACertFile.Get(); v != "" {
+		return filepath.SplitList(v)
+	}
+	return c.ReadWriter.CACertFiles()
+}
+
+func (c *pluginContext) ClientCertFile() string {
+	return envOrConfig(bluemix.EnvClientCertFile, c.ReadWriter.ClientCertFile())
+}
+
+func (c *pluginContext) ClientKeyFile() string {
+	return envOrConfig(bluemix.EnvClientKeyFile, c.ReadWriter.ClientKeyFile())
+}
+
 func (c *pluginContext) SSOType() string {
 	return c.TypeOfSSO()
 }
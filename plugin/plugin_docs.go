@@ -0,0 +1,370 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DocOptions configures the documentation generated from the plugin metadata
+type DocOptions struct {
+	// CLIName is the name of the CLI binary prepended to the namespaces and commands, default is 'ibmcloud'
+	CLIName string
+	// ManSection is the section of the man pages, default is '1'
+	ManSection string
+	// IncludeHidden includes the hidden commands and flags
+	IncludeHidden bool
+}
+
+func (o DocOptions) cliName() string {
+	if o.CLIName == "" {
+		return "ibmcloud"
+	}
+	return o.CLIName
+}
+
+func (o DocOptions) manSection() string {
+	if o.ManSection == "" {
+		return "1"
+	}
+	return o.ManSection
+}
+
+// docPage is the documentation of a namespace or a command
+type docPage struct {
+	Name        string // full qualified name, including the CLI name
+	IsCommand   bool
+	Description string
+	Aliases     []string
+	Stage       Stage
+	ReplacedBy  string // full qualified name of the replacing command, including the CLI name
+	RemovalDate string
+	Usage       string
+	Examples    string
+	Flags       []Flag
+
+	Namespaces []*docPage // sub namespaces of a namespace
+	Commands   []*docPage // commands of a namespace
+	Parent     *docPage   // namespace of a command or parent of a namespace, if defined by the plugin
+}
+
+// GenerateMarkdownDocs writes one Markdown page for each namespace and command of the plugin to
+// the directory, and returns the paths of the written files. It can be run from a 'go generate'
+// step, e.g. with a small program calling it with the metadata returned by the plugin:
+//
+//	//go:generate go run ./internal/gendocs
+func GenerateMarkdownDocs(metadata PluginMetadata, dir string, opts DocOptions) ([]string, error) {
+	return writeDocs(metadata, dir, opts, func(p *docPage) (string, string) {
+		return docFileName(p.Name, "_") + ".md", renderMarkdown(metadata, p, opts)
+	})
+}
+
+// GenerateManPages writes one roff man page for each namespace and command of the plugin to the
+// directory, and returns the paths of the written files
+func GenerateManPages(metadata PluginMetadata, dir string, opts DocOptions) ([]string, error) {
+	return writeDocs(metadata, dir, opts, func(p *docPage) (string, string) {
+		return docFileName(p.Name, "-") + "." + opts.manSection(), renderMan(metadata, p, opts)
+	})
+}
+
+func writeDocs(metadata PluginMetadata, dir string, opts DocOptions, render func(*docPage) (string, string)) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, page := range docPages(metadata, opts) {
+		name, content := render(page)
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return files, err
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// docPages returns the pages of the namespaces followed by the pages of the commands
+func docPages(metadata PluginMetadata, opts DocOptions) []*docPage {
+	cli := opts.cliName()
+	qualify := func(names ...string) string {
+		return strings.Join(strings.Fields(cli+" "+strings.Join(names, " ")), " ")
+	}
+
+	var pages []*docPage
+	namespaces := map[string]*docPage{}
+	for _, ns := range metadata.Namespaces {
+		page := &docPage{
+			Name:        qualify(ns.ParentName, ns.Name),
+			Description: ns.Description,
+			Aliases:     ns.Aliases,
+			Stage:       ns.Stage,
+		}
+		namespaces[page.Name] = page
+		pages = append(pages, page)
+	}
+	for _, ns := range metadata.Namespaces {
+		if parent, ok := namespaces[qualify(ns.ParentName)]; ok && ns.ParentName != "" {
+			page := namespaces[qualify(ns.ParentName, ns.Name)]
+			page.Parent = parent
+			parent.Namespaces = append(parent.Namespaces, page)
+		}
+	}
+
+	for _, cmd := range metadata.Commands {
+		if cmd.Hidden && !opts.IncludeHidden {
+			continue
+		}
+		usage, examples := splitUsage(cmd.Usage)
+		page := &docPage{
+			Name:        qualify(cmd.Namespace, cmd.Name),
+			IsCommand:   true,
+			Description: cmd.Description,
+			Aliases:     cmd.NameAndAliases()[1:],
+			Stage:       CommandStage(metadata, cmd),
+			RemovalDate: cmd.RemovalDate,
+			Usage:       usage,
+			Examples:    examples,
+		}
+		if cmd.ReplacedBy != "" {
+			page.ReplacedBy = qualify(cmd.ReplacedBy)
+		}
+		for _, f := range cmd.Flags {
+			if !f.Hidden || opts.IncludeHidden {
+				page.Flags = append(page.Flags, f)
+			}
+		}
+		if ns, ok := namespaces[qualify(cmd.Namespace)]; ok {
+			page.Parent = ns
+			ns.Commands = append(ns.Commands, page)
+		}
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// splitUsage splits the usage text of a command into the synopsis and the examples, which start
+// with a line such as 'EXAMPLE:' or 'EXAMPLES:'
+func splitUsage(usage string) (string, string) {
+	lines := strings.Split(usage, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(line)), "EXAMPLE") {
+			return dedent(lines[:i]), dedent(lines[i+1:])
+		}
+	}
+	return dedent(lines), ""
+}
+
+// dedent removes the indentation common to all non-blank lines and the surrounding blank lines
+func dedent(lines []string) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+
+	ret := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		ret[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Trim(strings.Join(ret, "\n"), "\n")
+}
+
+// flagNames returns the names of a flag as shown in the command help, e.g. '-n, --name value'
+func flagNames(f Flag) string {
+	var names []string
+	for _, n := range strings.Split(f.Name, ",") {
+		n = strings.TrimSpace(n)
+		if len(n) == 1 {
+			names = append(names, "-"+n)
+		} else {
+			names = append(names, "--"+n)
+		}
+	}
+	ret := strings.Join(names, ", ")
+	if f.HasValue {
+		ret += " value"
+	}
+	return ret
+}
+
+func stageTitle(stage Stage) string {
+	switch stage {
+	case StageExperimental:
+		return "Experimental"
+	case StageBeta:
+		return "Beta"
+	case StageDeprecated:
+		return "Deprecated"
+	}
+	return string(stage)
+}
+
+func docFileName(name string, sep string) string {
+	return strings.Join(strings.Fields(name), sep)
+}
+
+func renderMarkdown(metadata PluginMetadata, p *docPage, opts DocOptions) string {
+	var b strings.Builder
+	link := func(page *docPage) string {
+		return fmt.Sprintf("[%s](%s.md)", page.Name, docFileName(page.Name, "_"))
+	}
+
+	fmt.Fprintf(&b, "# %s", p.Name)
+	if p.Stage != "" {
+		fmt.Fprintf(&b, " `%s`", strings.ToUpper(stageTitle(p.Stage)))
+	}
+	b.WriteString("\n\n")
+
+	if p.Stage == StageDeprecated {
+		b.WriteString("> **Deprecated**: this ")
+		if p.IsCommand {
+			b.WriteString("command")
+		} else {
+			b.WriteString("namespace")
+		}
+		if p.RemovalDate != "" {
+			fmt.Fprintf(&b, " will be removed on %s.", p.RemovalDate)
+		} else {
+			b.WriteString(" will be removed in a future release.")
+		}
+		if p.ReplacedBy != "" {
+			fmt.Fprintf(&b, " Use `%s` instead.", p.ReplacedBy)
+		}
+		b.WriteString("\n\n")
+	}
+	if p.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", p.Description)
+	}
+
+	if len(p.Aliases) > 0 {
+		b.WriteString("## Aliases\n\n")
+		for _, a := range p.Aliases {
+			fmt.Fprintf(&b, "- `%s`\n", a)
+		}
+		b.WriteString("\n")
+	}
+
+	if p.IsCommand {
+		if p.Usage != "" {
+			fmt.Fprintf(&b, "## Usage\n\n```\n%s\n```\n\n", p.Usage)
+		}
+		if len(p.Flags) > 0 {
+			b.WriteString("## Options\n\n| Option | Description |\n| --- | --- |\n")
+			for _, f := range p.Flags {
+				fmt.Fprintf(&b, "| `%s` | %s |\n", flagNames(f), strings.ReplaceAll(f.Description, "|", `\|`))
+			}
+			b.WriteString("\n")
+		}
+		if p.Examples != "" {
+			fmt.Fprintf(&b, "## Examples\n\n```\n%s\n```\n\n", p.Examples)
+		}
+	} else {
+		if len(p.Namespaces) > 0 {
+			b.WriteString("## Namespaces\n\n")
+			for _, ns := range p.Namespaces {
+				fmt.Fprintf(&b, "- %s: %s\n", link(ns), ns.Description)
+			}
+			b.WriteString("\n")
+		}
+		if len(p.Commands) > 0 {
+			b.WriteString("## Commands\n\n")
+			for _, cmd := range p.Commands {
+				fmt.Fprintf(&b, "- %s: %s\n", link(cmd), cmd.Description)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if p.Parent != nil {
+		fmt.Fprintf(&b, "## See also\n\n- %s\n\n", link(p.Parent))
+	}
+	fmt.Fprintf(&b, "_Generated from the metadata of plug-in %s %s._\n", metadata.Name, metadata.Version)
+	return b.String()
+}
+
+func renderMan(metadata PluginMetadata, p *docPage, opts DocOptions) string {
+	var b strings.Builder
+	title := strings.ToUpper(docFileName(p.Name, "-"))
+
+	fmt.Fprintf(&b, ".TH \"%s\" \"%s\" \"\" \"%s %s\" \"%s\"\n",
+		roffEscape(title), opts.manSection(), roffEscape(metadata.Name), metadata.Version, roffEscape(opts.cliName()))
+
+	b.WriteString(".SH NAME\n")
+	fmt.Fprintf(&b, "%s", roffEscape(p.Name))
+	if p.Description != "" {
+		fmt.Fprintf(&b, " \\- %s", roffEscape(p.Description))
+	}
+	b.WriteString("\n")
+
+	if p.Usage != "" {
+		b.WriteString(".SH SYNOPSIS\n.nf\n")
+		fmt.Fprintf(&b, "%s\n", roffLines(p.Usage))
+		b.WriteString(".fi\n")
+	}
+
+	if p.Stage != "" {
+		b.WriteString(".SH STAGE\n")
+		fmt.Fprintf(&b, "%s\n", roffEscape(stageTitle(p.Stage)))
+	}
+
+	if len(p.Aliases) > 0 {
+		b.WriteString(".SH ALIASES\n")
+		fmt.Fprintf(&b, "%s\n", roffEscape(strings.Join(p.Aliases, ", ")))
+	}
+
+	if len(p.Flags) > 0 {
+		b.WriteString(".SH OPTIONS\n")
+		for _, f := range p.Flags {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(flagNames(f)), roffEscape(f.Description))
+		}
+	}
+
+	if !p.IsCommand && len(p.Namespaces)+len(p.Commands) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+		for _, child := range append(append([]*docPage{}, p.Namespaces...), p.Commands...) {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(child.Name), roffEscape(child.Description))
+		}
+	}
+
+	if p.Examples != "" {
+		b.WriteString(".SH EXAMPLES\n.nf\n")
+		fmt.Fprintf(&b, "%s\n", roffLines(p.Examples))
+		b.WriteString(".fi\n")
+	}
+
+	if p.Parent != nil {
+		b.WriteString(".SH SEE ALSO\n")
+		fmt.Fprintf(&b, "\\fB%s\\fR(%s)\n", roffEscape(docFileName(p.Parent.Name, "-")), opts.manSection())
+	}
+	return b.String()
+}
+
+// roffEscape escapes the text of a single line for roff
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	s = strings.ReplaceAll(s, "\n", " ")
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// roffLines escapes each line of a multi-line text for roff
+func roffLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = roffEscape(line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,168 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var docsMetadata = PluginMetadata{
+	Name:    "demo",
+	Version: VersionType{Major: 1, Minor: 0, Build: 2},
+	Namespaces: []Namespace{
+		{Name: "demo", Aliases: []string{"dm"}, Description: "Manage demo resources."},
+		{ParentName: "demo", Name: "legacy", Description: "Manage legacy resources.", Stage: StageDeprecated},
+	},
+	Commands: []Command{
+		{
+			Namespace:   "demo",
+			Name:        "list",
+			Aliases:     []string{"ls"},
+			Description: "List all demo resources.",
+			Usage: `ibmcloud demo list [--output FORMAT]
+
+EXAMPLES:
+   List the resources as JSON:
+   ibmcloud demo list --output json`,
+			Flags: []Flag{
+				{Name: "output", Description: "Specify output format, only 'JSON' is supported.", HasValue: true},
+				{Name: "q,quiet", Description: "Suppress verbose output"},
+				{Name: "debug-internal", Description: "Internal debugging", Hidden: true},
+			},
+			Stage: StageBeta,
+		},
+		{
+			Namespace:   "demo legacy",
+			Name:        "show",
+			Description: "Show a legacy resource.",
+			Usage:       "ibmcloud demo legacy show NAME",
+			ReplacedBy:  "demo list",
+		},
+		{
+			Namespace:   "demo",
+			Name:        "secret",
+			Description: "Do something hidden.",
+			Usage:       "ibmcloud demo secret",
+			Hidden:      true,
+		},
+	},
+}
+
+func TestSplitUsage(t *testing.T) {
+	usage, examples := splitUsage(docsMetadata.Commands[0].Usage)
+	assert.Equal(t, "ibmcloud demo list [--output FORMAT]", usage)
+	assert.Equal(t, "List the resources as JSON:\nibmcloud demo list --output json", examples)
+
+	usage, examples = splitUsage("ibmcloud demo legacy show NAME")
+	assert.Equal(t, "ibmcloud demo legacy show NAME", usage)
+	assert.Empty(t, examples)
+}
+
+func TestFlagNames(t *testing.T) {
+	assert.Equal(t, "--output value", flagNames(Flag{Name: "output", HasValue: true}))
+	assert.Equal(t, "-q, --quiet", flagNames(Flag{Name: "q,quiet"}))
+}
+
+func TestGenerateMarkdownDocs(t *testing.T) {
+	dir := t.TempDir()
+
+	files, err := GenerateMarkdownDocs(docsMetadata, dir, DocOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "ibmcloud_demo.md"),
+		filepath.Join(dir, "ibmcloud_demo_legacy.md"),
+		filepath.Join(dir, "ibmcloud_demo_list.md"),
+		filepath.Join(dir, "ibmcloud_demo_legacy_show.md"),
+	}, files)
+
+	list, _ := os.ReadFile(filepath.Join(dir, "ibmcloud_demo_list.md"))
+	assert.Equal(t, "# ibmcloud demo list `BETA`\n\n"+
+		"List all demo resources.\n\n"+
+		"## Aliases\n\n- `ls`\n\n"+
+		"## Usage\n\n```\nibmcloud demo list [--output FORMAT]\n```\n\n"+
+		"## Options\n\n| Option | Description |\n| --- | --- |\n"+
+		"| `--output value` | Specify output format, only 'JSON' is supported. |\n"+
+		"| `-q, --quiet` | Suppress verbose output |\n\n"+
+		"## Examples\n\n```\nList the resources as JSON:\nibmcloud demo list --output json\n```\n\n"+
+		"## See also\n\n- [ibmcloud demo](ibmcloud_demo.md)\n\n"+
+		"_Generated from the metadata of plug-in demo 1.0.2._\n", string(list))
+
+	ns, _ := os.ReadFile(filepath.Join(dir, "ibmcloud_demo.md"))
+	assert.Contains(t, string(ns), "## Namespaces\n\n- [ibmcloud demo legacy](ibmcloud_demo_legacy.md): Manage legacy resources.\n")
+	assert.Contains(t, string(ns), "## Commands\n\n- [ibmcloud demo list](ibmcloud_demo_list.md): List all demo resources.\n\n")
+	assert.NotContains(t, string(ns), "secret")
+
+	// commands inherit the stage of their namespace
+	show, _ := os.ReadFile(filepath.Join(dir, "ibmcloud_demo_legacy_show.md"))
+	assert.Contains(t, string(show), "# ibmcloud demo legacy show `DEPRECATED`\n\n> **Deprecated**: this command will be removed in a future release. Use `ibmcloud demo list` instead.\n")
+}
+
+func TestGenerateMarkdownDocsInheritedStage(t *testing.T) {
+	metadata := docsMetadata
+	metadata.Namespaces = append([]Namespace{{ParentName: "demo legacy", Name: "archive", Description: "Manage archives."}}, docsMetadata.Namespaces...)
+	metadata.Commands = []Command{{Namespace: "demo legacy archive", Name: "purge", Description: "Purge the archives.", Usage: "ibmcloud demo legacy archive purge"}}
+	dir := t.TempDir()
+
+	_, err := GenerateMarkdownDocs(metadata, dir, DocOptions{})
+	assert.NoError(t, err)
+	// commands inherit the stage of the closest namespace having one
+	purge, _ := os.ReadFile(filepath.Join(dir, "ibmcloud_demo_legacy_archive_purge.md"))
+	assert.Contains(t, string(purge), "# ibmcloud demo legacy archive purge `DEPRECATED`\n")
+}
+
+func TestGenerateMarkdownDocsIncludeHidden(t *testing.T) {
+	dir := t.TempDir()
+
+	files, err := GenerateMarkdownDocs(docsMetadata, dir, DocOptions{CLIName: "ic", IncludeHidden: true})
+	assert.NoError(t, err)
+	assert.Contains(t, files, filepath.Join(dir, "ic_demo_secret.md"))
+
+	list, _ := os.ReadFile(filepath.Join(dir, "ic_demo_list.md"))
+	assert.Contains(t, string(list), "| `--debug-internal` | Internal debugging |")
+}
+
+func TestGenerateManPages(t *testing.T) {
+	dir := t.TempDir()
+
+	files, err := GenerateManPages(docsMetadata, dir, DocOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, files, 4)
+
+	list, _ := os.ReadFile(filepath.Join(dir, "ibmcloud-demo-list.1"))
+	assert.Equal(t, `.TH "IBMCLOUD\-DEMO\-LIST" "1" "" "demo 1.0.2" "ibmcloud"
+.SH NAME
+ibmcloud demo list \- List all demo resources.
+.SH SYNOPSIS
+.nf
+ibmcloud demo list [\-\-output FORMAT]
+.fi
+.SH STAGE
+Beta
+.SH ALIASES
+ls
+.SH OPTIONS
+.TP
+\fB\-\-output value\fR
+Specify output format, only 'JSON' is supported.
+.TP
+\fB\-q, \-\-quiet\fR
+Suppress verbose output
+.SH EXAMPLES
+.nf
+List the resources as JSON:
+ibmcloud demo list \-\-output json
+.fi
+.SH SEE ALSO
+\fBibmcloud\-demo\fR(1)
+`, string(list))
+
+	ns, _ := os.ReadFile(filepath.Join(dir, "ibmcloud-demo.1"))
+	assert.Contains(t, string(ns), ".SH COMMANDS\n.TP\n\\fBibmcloud demo legacy\\fR\nManage legacy resources.\n.TP\n\\fBibmcloud demo list\\fR\n")
+}
+
+func TestRoffEscape(t *testing.T) {
+	assert.Equal(t, `\&.hidden`, roffEscape(".hidden"))
+	assert.Equal(t, `a\eb \-c`, roffEscape(`a\b -c`))
+}
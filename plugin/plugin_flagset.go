@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"flag"
+	"strings"
+)
+
+// FlagSetCommand describes a plugin command whose flags are parsed by a flag.FlagSet of the
+// standard library, the command being named after the flag set
+type FlagSetCommand struct {
+	FlagSet     *flag.FlagSet
+	Aliases     []string // command aliases
+	Description string   // short description of the command
+	ArgsUsage   string   // usage of the arguments, e.g. 'NAME [VERSION]'
+	Hidden      bool     // true to hide the command in help text
+}
+
+// ConvertFlagSetToPluginFlags will convert flags defined by the flag package of the standard library
+// to Plugin Flags. Method is used when defining the Flags in command metadata. @see Plugin#GetMetadata() for use case
+func ConvertFlagSetToPluginFlags(fs *flag.FlagSet) []Flag {
+	var flags []Flag
+	fs.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, Flag{
+			Name:        f.Name,
+			Description: f.Usage,
+			HasValue:    !isBoolFlag(f),
+		})
+	})
+	return flags
+}
+
+// ConvertFlagSetCommandsToPluginCommands will convert commands whose flags are defined by the flag
+// package of the standard library to Plugin Commands of the namespace. The usage is built from the
+// command name, the ArgsUsage and the flags.
+func ConvertFlagSetCommandsToPluginCommands(namespace string, commands []FlagSetCommand) []Command {
+	var ret []Command
+	for _, c := range commands {
+		words := append([]string{"ibmcloud", namespace, c.FlagSet.Name()}, strings.Fields(c.ArgsUsage)...)
+		c.FlagSet.VisitAll(func(f *flag.Flag) {
+			valueName, _ := flag.UnquoteUsage(f)
+			if valueName == "" {
+				valueName = "value"
+			}
+			words = append(words, FlagUsage([]string{f.Name}, !isBoolFlag(f), valueName))
+		})
+
+		ret = append(ret, Command{
+			Namespace:   namespace,
+			Name:        c.FlagSet.Name(),
+			Aliases:     c.Aliases,
+			Description: c.Description,
+			Usage:       strings.Join(strings.Fields(strings.Join(words, " ")), " "),
+			Flags:       ConvertFlagSetToPluginFlags(c.FlagSet),
+			Hidden:      c.Hidden,
+		})
+	}
+	return ret
+}
+
+// isBoolFlag returns whether the flag can be set without a value, like the flag package does
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+// FlagUsage returns the usage of a flag with the given names, e.g. '[--output FORMAT]', using its
+// first long name. It is used to build the usage of converted commands.
+func FlagUsage(names []string, hasValue bool, valueName string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	name := "-" + names[0]
+	for _, n := range names {
+		if len(n) > 1 {
+			name = "--" + n
+			break
+		}
+	}
+	if hasValue {
+		name += " " + strings.ToUpper(valueName)
+	}
+	return "[" + name + "]"
+}
@@ -0,0 +1,43 @@
+package plugin
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.String("output", "", "Specify the output `FORMAT`")
+	fs.Bool("q", false, "Suppress verbose output")
+	fs.Int("limit", 100, "Maximum number of resources")
+	return fs
+}
+
+func TestConvertFlagSetToPluginFlags(t *testing.T) {
+	assert.Equal(t, []Flag{
+		{Name: "limit", Description: "Maximum number of resources", HasValue: true},
+		{Name: "output", Description: "Specify the output `FORMAT`", HasValue: true},
+		{Name: "q", Description: "Suppress verbose output"},
+	}, ConvertFlagSetToPluginFlags(newTestFlagSet()))
+}
+
+func TestConvertFlagSetCommandsToPluginCommands(t *testing.T) {
+	commands := ConvertFlagSetCommandsToPluginCommands("demo", []FlagSetCommand{
+		{FlagSet: newTestFlagSet(), Aliases: []string{"ls"}, Description: "List all demo resources.", ArgsUsage: "[NAME]"},
+		{FlagSet: flag.NewFlagSet("secret", flag.ContinueOnError), Description: "Do something hidden.", Hidden: true},
+	})
+
+	if assert.Len(t, commands, 2) {
+		assert.Equal(t, "demo", commands[0].Namespace)
+		assert.Equal(t, "list", commands[0].Name)
+		assert.Equal(t, []string{"ls"}, commands[0].Aliases)
+		assert.Equal(t, "List all demo resources.", commands[0].Description)
+		assert.Equal(t, "ibmcloud demo list [NAME] [--limit INT] [--output FORMAT] [-q]", commands[0].Usage)
+		assert.Len(t, commands[0].Flags, 3)
+
+		assert.Equal(t, "ibmcloud demo secret", commands[1].Usage)
+		assert.True(t, commands[1].Hidden)
+	}
+}
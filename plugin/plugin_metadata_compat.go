@@ -0,0 +1,253 @@
+package plugin
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// VersionBump is the kind of change between two plugin versions
+type VersionBump int
+
+const (
+	BumpNone  VersionBump = iota // same version
+	BumpBuild                    // build number increased
+	BumpMinor                    // minor version increased
+	BumpMajor                    // major version increased
+)
+
+func (b VersionBump) String() string {
+	switch b {
+	case BumpBuild:
+		return "build"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	}
+	return "none"
+}
+
+// MetadataChange is a change of the plugin metadata between two versions
+type MetadataChange struct {
+	Breaking    bool   `json:"breaking"`          // whether the change can break the scripts of the users
+	Command     string `json:"command,omitempty"` // full qualified name of the command, if the change is about a command
+	Description string `json:"description"`       // description of the change
+}
+
+// CompatibilityReport is the result of the comparison of two versions of the plugin metadata
+type CompatibilityReport struct {
+	Name         string           `json:"name"`
+	OldVersion   VersionType      `json:"oldVersion"`
+	NewVersion   VersionType      `json:"newVersion"`
+	Changes      []MetadataChange `json:"changes"`
+	ActualBump   VersionBump      `json:"actualBump"`   // version bump between the old and new metadata
+	RequiredBump VersionBump      `json:"requiredBump"` // minimum version bump required by the changes
+}
+
+// CompareMetadata compares the metadata of two versions of a plugin. Removed commands, aliases
+// and flags, flags whose value changed to required or not, and a raised MinCliVersion are breaking
+// changes. Added commands, aliases, flags and namespaces are non-breaking changes.
+func CompareMetadata(oldMetadata PluginMetadata, newMetadata PluginMetadata) CompatibilityReport {
+	r := CompatibilityReport{
+		Name:       newMetadata.Name,
+		OldVersion: oldMetadata.Version,
+		NewVersion: newMetadata.Version,
+		Changes:    []MetadataChange{},
+		ActualBump: versionBump(oldMetadata.Version, newMetadata.Version),
+	}
+
+	if compareVersions(newMetadata.MinCliVersion, oldMetadata.MinCliVersion) > 0 {
+		r.add(true, "", fmt.Sprintf("minimum CLI version raised from %s to %s", oldMetadata.MinCliVersion, newMetadata.MinCliVersion))
+	}
+	r.compareNames("plugin alias", "", oldMetadata.Aliases, newMetadata.Aliases)
+
+	oldNamespaces := namespacesByName(oldMetadata.Namespaces)
+	newNamespaces := namespacesByName(newMetadata.Namespaces)
+	for _, name := range sortedKeys(oldNamespaces) {
+		if n, ok := newNamespaces[name]; ok {
+			r.compareNames("namespace alias", "", prefixed(name, oldNamespaces[name].Aliases), prefixed(name, n.Aliases))
+		} else {
+			r.add(true, "", fmt.Sprintf("namespace '%s' removed", name))
+		}
+	}
+	for _, name := range sortedKeys(newNamespaces) {
+		if _, ok := oldNamespaces[name]; !ok {
+			r.add(false, "", fmt.Sprintf("namespace '%s' added", name))
+		}
+	}
+
+	oldCommands := commandsByName(oldMetadata.Commands)
+	newCommands := commandsByName(newMetadata.Commands)
+	for _, name := range sortedKeys(oldCommands) {
+		if c, ok := newCommands[name]; ok {
+			r.compareCommand(name, oldCommands[name], c)
+		} else {
+			r.add(true, name, fmt.Sprintf("command '%s' removed", name))
+		}
+	}
+	for _, name := range sortedKeys(newCommands) {
+		if _, ok := oldCommands[name]; !ok {
+			r.add(false, name, fmt.Sprintf("command '%s' added", name))
+		}
+	}
+
+	for _, c := range r.Changes {
+		required := BumpMinor
+		if c.Breaking {
+			required = BumpMajor
+			// versions 0.x.y are unstable, a breaking change only requires a new minor version
+			if oldMetadata.Version.Major == 0 {
+				required = BumpMinor
+			}
+		}
+		if required > r.RequiredBump {
+			r.RequiredBump = required
+		}
+	}
+	return r
+}
+
+func (r *CompatibilityReport) add(breaking bool, command string, description string) {
+	r.Changes = append(r.Changes, MetadataChange{Breaking: breaking, Command: command, Description: description})
+}
+
+// compareNames reports the removed and added names
+func (r *CompatibilityReport) compareNames(kind string, command string, oldNames []string, newNames []string) {
+	for _, n := range oldNames {
+		if !slices.Contains(newNames, n) {
+			r.add(true, command, fmt.Sprintf("%s '%s' removed", kind, n))
+		}
+	}
+	for _, n := range newNames {
+		if !slices.Contains(oldNames, n) {
+			r.add(false, command, fmt.Sprintf("%s '%s' added", kind, n))
+		}
+	}
+}
+
+func (r *CompatibilityReport) compareCommand(name string, oldCmd Command, newCmd Command) {
+	r.compareNames("command alias", name, prefixed(oldCmd.Namespace, oldCmd.NameAndAliases()[1:]), prefixed(newCmd.Namespace, newCmd.NameAndAliases()[1:]))
+
+	oldFlags := flagsByName(oldCmd.Flags)
+	newFlags := flagsByName(newCmd.Flags)
+	for _, n := range sortedKeys(oldFlags) {
+		f, ok := newFlags[n]
+		switch {
+		case !ok:
+			r.add(true, name, fmt.Sprintf("flag '%s' removed", n))
+		case oldFlags[n].HasValue && !f.HasValue:
+			r.add(true, name, fmt.Sprintf("flag '%s' no longer takes a value", n))
+		case !oldFlags[n].HasValue && f.HasValue:
+			r.add(true, name, fmt.Sprintf("flag '%s' now requires a value", n))
+		}
+	}
+	for _, n := range sortedKeys(newFlags) {
+		if _, ok := oldFlags[n]; !ok {
+			r.add(false, name, fmt.Sprintf("flag '%s' added", n))
+		}
+	}
+}
+
+// Breaking returns the breaking changes
+func (r CompatibilityReport) Breaking() []MetadataChange {
+	var changes []MetadataChange
+	for _, c := range r.Changes {
+		if c.Breaking {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Err returns an error if the version bump is lower than the one required by the changes, or if
+// the new version is lower than the old one
+func (r CompatibilityReport) Err() error {
+	if compareVersions(r.NewVersion, r.OldVersion) < 0 {
+		return fmt.Errorf("version %s of plugin '%s' is lower than the previous version %s", r.NewVersion, r.Name, r.OldVersion)
+	}
+	if r.ActualBump < r.RequiredBump {
+		return fmt.Errorf("plugin '%s' has %d breaking and %d non-breaking change(s) which require a %s version bump, but version %s to %s is a %s version bump",
+			r.Name, len(r.Breaking()), len(r.Changes)-len(r.Breaking()), r.RequiredBump, r.OldVersion, r.NewVersion, r.ActualBump)
+	}
+	return nil
+}
+
+// String renders the report, one change per line
+func (r CompatibilityReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s -> %s (%s version bump, %s required)\n", r.Name, r.OldVersion, r.NewVersion, r.ActualBump, r.RequiredBump)
+	for _, c := range r.Changes {
+		kind := "non-breaking"
+		if c.Breaking {
+			kind = "BREAKING"
+		}
+		fmt.Fprintf(&b, "  %-12s %s\n", kind, c.Description)
+	}
+	return b.String()
+}
+
+// compareVersions returns -1, 0 or 1 if a is lower than, equal to or greater than b
+func compareVersions(a VersionType, b VersionType) int {
+	for _, d := range []int{a.Major - b.Major, a.Minor - b.Minor, a.Build - b.Build} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionBump(oldVersion VersionType, newVersion VersionType) VersionBump {
+	switch {
+	case newVersion.Major > oldVersion.Major:
+		return BumpMajor
+	case newVersion.Major < oldVersion.Major:
+		return BumpNone
+	case newVersion.Minor > oldVersion.Minor:
+		return BumpMinor
+	case newVersion.Minor < oldVersion.Minor:
+		return BumpNone
+	case newVersion.Build > oldVersion.Build:
+		return BumpBuild
+	}
+	return BumpNone
+}
+
+func namespacesByName(namespaces []Namespace) map[string]Namespace {
+	m := make(map[string]Namespace, len(namespaces))
+	for _, n := range namespaces {
+		m[strings.TrimSpace(n.ParentName+" "+n.Name)] = n
+	}
+	return m
+}
+
+func commandsByName(commands []Command) map[string]Command {
+	m := make(map[string]Command, len(commands))
+	for _, c := range commands {
+		m[strings.TrimSpace(c.Namespace+" "+c.Name)] = c
+	}
+	return m
+}
+
+// flagsByName maps each name of the flags, a flag such as 'q,quiet' having two names
+func flagsByName(flags []Flag) map[string]Flag {
+	m := make(map[string]Flag, len(flags))
+	for _, f := range flags {
+		for _, n := range strings.Split(f.Name, ",") {
+			m[strings.TrimSpace(n)] = f
+		}
+	}
+	return m
+}
+
+// prefixed returns the names with the prefix, so that aliases are reported with their namespace
+func prefixed(prefix string, names []string) []string {
+	ret := make([]string, len(names))
+	for i, n := range names {
+		ret[i] = strings.TrimSpace(prefix + " " + n)
+	}
+	return ret
+}
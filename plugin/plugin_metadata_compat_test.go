@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func compatMetadata() PluginMetadata {
+	return PluginMetadata{
+		Name:          "demo",
+		Version:       VersionType{Major: 1, Minor: 2},
+		MinCliVersion: VersionType{Major: 2},
+		Namespaces:    []Namespace{{Name: "demo", Aliases: []string{"dm"}}},
+		Commands: []Command{
+			{
+				Namespace: "demo",
+				Name:      "list",
+				Aliases:   []string{"ls"},
+				Flags:     []Flag{{Name: "output", HasValue: true}, {Name: "q,quiet"}},
+			},
+			{Namespace: "demo", Name: "show"},
+		},
+	}
+}
+
+func TestCompareMetadataNoChanges(t *testing.T) {
+	newMetadata := compatMetadata()
+	newMetadata.Version.Build = 1
+
+	r := CompareMetadata(compatMetadata(), newMetadata)
+	assert.Empty(t, r.Changes)
+	assert.Equal(t, BumpBuild, r.ActualBump)
+	assert.Equal(t, BumpNone, r.RequiredBump)
+	assert.NoError(t, r.Err())
+}
+
+func TestCompareMetadataNonBreaking(t *testing.T) {
+	newMetadata := compatMetadata()
+	newMetadata.Version = VersionType{Major: 1, Minor: 2, Build: 1}
+	newMetadata.Namespaces = append(newMetadata.Namespaces, Namespace{ParentName: "demo", Name: "legacy"})
+	newMetadata.Commands = append(newMetadata.Commands, Command{Namespace: "demo legacy", Name: "migrate"})
+	newMetadata.Commands[1].Flags = []Flag{{Name: "output", HasValue: true}}
+
+	r := CompareMetadata(compatMetadata(), newMetadata)
+	assert.Equal(t, []MetadataChange{
+		{Description: "namespace 'demo legacy' added"},
+		{Command: "demo show", Description: "flag 'output' added"},
+		{Command: "demo legacy migrate", Description: "command 'demo legacy migrate' added"},
+	}, r.Changes)
+	assert.Empty(t, r.Breaking())
+	assert.Equal(t, BumpMinor, r.RequiredBump)
+	assert.EqualError(t, r.Err(), "plugin 'demo' has 0 breaking and 3 non-breaking change(s) which require a minor version bump, but version 1.2.0 to 1.2.1 is a build version bump")
+
+	newMetadata.Version = VersionType{Major: 1, Minor: 3}
+	assert.NoError(t, CompareMetadata(compatMetadata(), newMetadata).Err())
+}
+
+func TestCompareMetadataBreaking(t *testing.T) {
+	newMetadata := compatMetadata()
+	newMetadata.Version = VersionType{Major: 1, Minor: 3}
+	newMetadata.MinCliVersion = VersionType{Major: 2, Minor: 1}
+	newMetadata.Namespaces[0].Aliases = nil
+	newMetadata.Commands = []Command{
+		{
+			Namespace: "demo",
+			Name:      "list",
+			Flags:     []Flag{{Name: "output"}, {Name: "quiet"}},
+		},
+	}
+
+	r := CompareMetadata(compatMetadata(), newMetadata)
+	assert.Equal(t, []MetadataChange{
+		{Breaking: true, Description: "minimum CLI version raised from 2.0.0 to 2.1.0"},
+		{Breaking: true, Description: "namespace alias 'demo dm' removed"},
+		{Breaking: true, Command: "demo list", Description: "command alias 'demo ls' removed"},
+		{Breaking: true, Command: "demo list", Description: "flag 'output' no longer takes a value"},
+		{Breaking: true, Command: "demo list", Description: "flag 'q' removed"},
+		{Breaking: true, Command: "demo show", Description: "command 'demo show' removed"},
+	}, r.Changes)
+	assert.Len(t, r.Breaking(), 6)
+	assert.Equal(t, BumpMajor, r.RequiredBump)
+	assert.Error(t, r.Err())
+	assert.Contains(t, r.String(), "demo 1.2.0 -> 1.3.0 (minor version bump, major required)\n")
+	assert.Contains(t, r.String(), "  BREAKING     command 'demo show' removed\n")
+
+	newMetadata.Version = VersionType{Major: 2}
+	assert.NoError(t, CompareMetadata(compatMetadata(), newMetadata).Err())
+}
+
+func TestCompareMetadataUnstableVersion(t *testing.T) {
+	oldMetadata := compatMetadata()
+	oldMetadata.Version = VersionType{Minor: 4}
+	newMetadata := compatMetadata()
+	newMetadata.Version = VersionType{Minor: 5}
+	newMetadata.Commands = newMetadata.Commands[:1]
+
+	r := CompareMetadata(oldMetadata, newMetadata)
+	assert.Equal(t, BumpMinor, r.RequiredBump)
+	assert.NoError(t, r.Err())
+}
+
+func TestCompareMetadataLowerVersion(t *testing.T) {
+	newMetadata := compatMetadata()
+	newMetadata.Version = VersionType{Major: 1, Minor: 1, Build: 9}
+
+	assert.EqualError(t, CompareMetadata(compatMetadata(), newMetadata).Err(), "version 1.1.9 of plugin 'demo' is lower than the previous version 1.2.0")
+}
@@ -0,0 +1,335 @@
+package plugin
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/i18n"
+	"github.com/go-playground/validator/v10"
+)
+
+// Suggestion is a proposed fix of a metadata validation error
+type Suggestion struct {
+	Path  string `json:"path"`           // path of the field to fix, in the form of PluginMetadataError.Namespace
+	Value string `json:"value"`          // proposed value of the field
+	Safe  bool   `json:"safe"`           // whether the fix can be applied without changing the meaning of the metadata
+	Hint  string `json:"hint,omitempty"` // how to fix the field when the value cannot be worked out, e.g. rewording a description
+}
+
+// FieldChange is the change of a metadata field made by ApplySuggestions
+type FieldChange struct {
+	Path string `json:"path"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// MetadataDiff lists the changes made by ApplySuggestions
+type MetadataDiff []FieldChange
+
+// String renders the changes in a unified diff like format
+func (d MetadataDiff) String() string {
+	var b strings.Builder
+	for _, c := range d {
+		fmt.Fprintf(&b, "%s\n- %s\n+ %s\n", c.Path, c.Old, c.New)
+	}
+	return b.String()
+}
+
+// verbSynonyms maps verbs commonly used in command names to the standard verb
+var verbSynonyms = map[string]string{
+	"rm": "delete", "del": "delete", "destroy": "delete",
+	"ls": "list", "new": "create", "make": "create",
+	"describe": "show", "info": "show", "inspect": "show",
+	"edit": "update", "modify": "update",
+	"fetch": "get",
+}
+
+var lowercaseWordRegexp = regexp.MustCompile(`\b[a-z][a-z_-]+\b`)
+
+// suggestFix returns a fix of the struct validation error of the command, nil if there is none
+func suggestFix(fieldErr validator.FieldError, cmdName string) *Suggestion {
+	path := fieldErr.StructNamespace()
+	value, _ := fieldErr.Value().(string)
+
+	switch fieldErr.Tag() {
+	case "uppercase":
+		return &Suggestion{Path: path, Value: upperFirst(value), Safe: true}
+	case "excludesall":
+		return &Suggestion{Path: path, Value: strings.Map(func(r rune) rune {
+			if strings.ContainsRune(fieldErr.Param(), r) {
+				return -1
+			}
+			return r
+		}, value), Safe: true}
+	case "capargs":
+		return &Suggestion{Path: path, Value: capitalizeArgs(value, strings.Split(fieldErr.Param(), ", ")), Safe: true}
+	case "mincliversion":
+		// raising the minimum CLI version drops the support of older CLIs
+		return &Suggestion{Path: path, Value: fieldErr.Param()}
+	case "nosubject":
+		trimmed := strings.TrimSpace(value[len(fieldErr.Param()):])
+		if trimmed == "" {
+			return nil
+		}
+		return &Suggestion{Path: path, Value: upperFirst(trimmed)}
+	case "cmdwordcount":
+		max, _ := strconv.Atoi(fieldErr.Param())
+		if i := strings.Index(value, ". "); i > 0 && len(strings.Fields(value[:i+1])) <= max {
+			return &Suggestion{Path: path, Value: value[:i+1]}
+		}
+	case "cleardesc":
+		// the command either acts on a single item and should be named in singular form, or lists
+		// items and the description should say so, which cannot be worked out from the metadata
+		if singular := singularName(cmdName); singular != "" {
+			return &Suggestion{
+				Path:  strings.TrimSuffix(path, ".Description") + ".Name",
+				Value: singular,
+				Hint: i18n.T("Rename the command to '{{.Name}}' if it acts on a single item, otherwise say in the description that it returns multiple items, e.g. 'List all ...'.", map[string]any{
+					"Name": singular,
+				}),
+			}
+		}
+		return &Suggestion{Path: path, Hint: i18n.T("Say in the description that the command returns multiple items, e.g. 'List all ...'.")}
+	case "usage":
+		if closed, ok := closeGroupings(value); ok && closed != value {
+			return &Suggestion{Path: path, Value: closed}
+		}
+	case "noverb":
+		name, _ := fieldErr.Value().(string)
+		words := strings.Split(name, "-")
+		for i, word := range words {
+			if verb, ok := verbSynonyms[word]; ok {
+				words[i] = verb
+				return &Suggestion{Path: path, Value: strings.Join(words, "-")}
+			}
+		}
+	}
+	return nil
+}
+
+// singularName returns the command name with its last word in singular form, e.g. 'instance-key'
+// for 'instance-keys', or an empty string if the last word is not a regular plural. Words ending
+// in 'ie', such as 'cookies', are not told apart from words ending in 'y', which is one reason
+// why the rename is never applied automatically.
+func singularName(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ss"):
+		return ""
+	case strings.HasSuffix(name, "ies") && len(name) > 3 && isConsonant(name[len(name)-4]):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return ""
+}
+
+func isConsonant(c byte) bool {
+	return c >= 'a' && c <= 'z' && !strings.ContainsRune("aeiou", rune(c))
+}
+
+// suggestPluginName returns a lowercase name with hyphens for a plugin name such as 'MyService'
+func suggestPluginName(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '-' && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteRune('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// capitalizeArgs converts the given lowercase arguments of the usage to CAPITAL letters, leaving
+// flags and paths untouched
+func capitalizeArgs(usage string, args []string) string {
+	lowercase := make(map[string]bool, len(args))
+	for _, arg := range args {
+		lowercase[arg] = true
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range lowercaseWordRegexp.FindAllStringIndex(usage, -1) {
+		word := usage[loc[0]:loc[1]]
+		if !lowercase[word] || loc[0] > 0 && strings.ContainsRune("-/", rune(usage[loc[0]-1])) {
+			continue
+		}
+		b.WriteString(usage[last:loc[0]])
+		b.WriteString(strings.ToUpper(strings.ReplaceAll(word, "-", "_")))
+		last = loc[1]
+	}
+	b.WriteString(usage[last:])
+	return b.String()
+}
+
+// closeGroupings appends the closing characters of the groupings left open at the end of the
+// usage. It returns false if a grouping is closed by a mismatched character.
+func closeGroupings(usage string) (string, bool) {
+	closers := map[rune]rune{'(': ')', '[': ']', '{': '}', '<': '>'}
+	var stack []rune
+	for _, r := range usage {
+		switch r {
+		case '(', '[', '{', '<':
+			stack = append(stack, r)
+		case ')', ']', '}', '>':
+			if len(stack) > 0 {
+				if closers[stack[len(stack)-1]] != r {
+					return usage, false
+				}
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	closed := strings.TrimRight(usage, " ")
+	for i := len(stack) - 1; i >= 0; i-- {
+		closed += string(closers[stack[i]])
+	}
+	return closed, true
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// FixMetadata validates the metadata and applies the safe suggestions until there is nothing
+// left to fix. It returns the corrected copy of the metadata and the changes made.
+func FixMetadata(metadata PluginMetadata) (PluginMetadata, MetadataDiff, error) {
+	var diff MetadataDiff
+	v := NewPluginMetadataValidator()
+	// fixes of the same field are applied one at a time, a field has at most a few of them
+	for i := 0; i < 5; i++ {
+		var errs []PluginMetadataError
+		for _, e := range v.Errors([]PluginMetadata{metadata}) {
+			errs = append(errs, e...)
+		}
+
+		fixed, changes, err := ApplySuggestions(metadata, errs)
+		if err != nil {
+			return metadata, diff, err
+		}
+		if len(changes) == 0 {
+			break
+		}
+		metadata = fixed
+		diff = mergeChanges(diff, changes)
+	}
+	return metadata, diff, nil
+}
+
+// mergeChanges adds the changes to the diff, keeping one change per field
+func mergeChanges(diff MetadataDiff, changes MetadataDiff) MetadataDiff {
+	for _, c := range changes {
+		merged := false
+		for i := range diff {
+			if diff[i].Path == c.Path {
+				diff[i].New = c.New
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			diff = append(diff, c)
+		}
+	}
+	return diff
+}
+
+// ApplySuggestions applies the safe suggestions of the errors to a copy of the metadata and
+// returns it with the changes made. Only the first suggestion of a field is applied, as the
+// suggestions are computed from the original value of the field.
+func ApplySuggestions(metadata PluginMetadata, errs []PluginMetadataError) (PluginMetadata, MetadataDiff, error) {
+	fixed := copyMetadata(metadata)
+	diff := MetadataDiff{}
+	applied := map[string]bool{}
+
+	for _, e := range errs {
+		s := e.Suggestion
+		if s == nil || !s.Safe || applied[s.Path] {
+			continue
+		}
+
+		old, err := setMetadataField(&fixed, s.Path, s.Value)
+		if err != nil {
+			return metadata, nil, err
+		}
+		applied[s.Path] = true
+		if old != s.Value {
+			diff = append(diff, FieldChange{Path: s.Path, Old: old, New: s.Value})
+		}
+	}
+	return fixed, diff, nil
+}
+
+// copyMetadata copies the metadata so that the fields of its namespaces, commands and flags can
+// be modified without changing the original
+func copyMetadata(metadata PluginMetadata) PluginMetadata {
+	metadata.Aliases = append([]string(nil), metadata.Aliases...)
+	metadata.Namespaces = append([]Namespace(nil), metadata.Namespaces...)
+	metadata.Commands = append([]Command(nil), metadata.Commands...)
+	for i := range metadata.Commands {
+		metadata.Commands[i].Flags = append([]Flag(nil), metadata.Commands[i].Flags...)
+	}
+	return metadata
+}
+
+var pathSegmentRegexp = regexp.MustCompile(`^(\w+)(?:\[(\d+)\])?$`)
+
+// setMetadataField sets the string or version field at the path (e.g. PluginMetadata.Commands[0].Usage)
+// and returns its previous value
+func setMetadataField(metadata *PluginMetadata, path string, value string) (string, error) {
+	segments := strings.Split(path, ".")
+	if len(segments) < 2 || segments[0] != "PluginMetadata" {
+		return "", fmt.Errorf("invalid metadata path '%s'", path)
+	}
+
+	field := reflect.ValueOf(metadata).Elem()
+	for _, segment := range segments[1:] {
+		m := pathSegmentRegexp.FindStringSubmatch(segment)
+		if m == nil || field.Kind() != reflect.Struct {
+			return "", fmt.Errorf("invalid metadata path '%s'", path)
+		}
+		field = field.FieldByName(m[1])
+		if !field.IsValid() {
+			return "", fmt.Errorf("invalid metadata path '%s'", path)
+		}
+		if m[2] != "" {
+			idx, _ := strconv.Atoi(m[2])
+			if field.Kind() != reflect.Slice || idx >= field.Len() {
+				return "", fmt.Errorf("invalid metadata path '%s'", path)
+			}
+			field = field.Index(idx)
+		}
+	}
+
+	switch field.Interface().(type) {
+	case string:
+		old := field.String()
+		field.SetString(value)
+		return old, nil
+	case VersionType:
+		version, err := parseVersionType(value)
+		if err != nil {
+			return "", err
+		}
+		old := field.Interface().(VersionType).String()
+		field.Set(reflect.ValueOf(version))
+		return old, nil
+	}
+	return "", fmt.Errorf("unsupported metadata field '%s'", path)
+}
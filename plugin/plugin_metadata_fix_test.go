@@ -0,0 +1,147 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fixableMetadata() PluginMetadata {
+	return PluginMetadata{
+		Name:          "DemoService",
+		Version:       VersionType{Major: 1},
+		MinCliVersion: VersionType{Major: 1, Minor: 5},
+		Namespaces:    []Namespace{{Name: "demo", Description: "Manage demo resources."}},
+		Commands: []Command{
+			{
+				Namespace:   "demo",
+				Name:        "show",
+				Description: "show a demo resource.",
+				Usage:       "ibmcloud demo show name [--output format] [--file /tmp/out]",
+				Flags:       []Flag{{Name: "<output>", Description: "Output format."}},
+			},
+			{
+				Namespace:   "demo",
+				Name:        "instance-rm",
+				Description: "This command deletes a demo instance.",
+				Usage:       "ibmcloud demo instance-rm INSTANCE [--force",
+			},
+		},
+	}
+}
+
+func suggestionsByPath(metadata PluginMetadata) map[string][]Suggestion {
+	suggestions := map[string][]Suggestion{}
+	for _, errs := range NewPluginMetadataValidator().Errors([]PluginMetadata{metadata}) {
+		for _, e := range errs {
+			if e.Suggestion != nil {
+				suggestions[e.Suggestion.Path] = append(suggestions[e.Suggestion.Path], *e.Suggestion)
+			}
+		}
+	}
+	return suggestions
+}
+
+func TestErrorSuggestions(t *testing.T) {
+	suggestions := suggestionsByPath(fixableMetadata())
+
+	assert.Equal(t, []Suggestion{{Path: "PluginMetadata.Name", Value: "demo-service"}}, suggestions["PluginMetadata.Name"])
+	assert.Equal(t, []Suggestion{{Path: "PluginMetadata.MinCliVersion", Value: "2.0.0"}}, suggestions["PluginMetadata.MinCliVersion"])
+	assert.Equal(t, []Suggestion{{Path: "PluginMetadata.Commands[0].Description", Value: "Show a demo resource.", Safe: true}}, suggestions["PluginMetadata.Commands[0].Description"])
+	assert.Equal(t, []Suggestion{{Path: "PluginMetadata.Commands[0].Usage", Value: "ibmcloud demo show NAME [--output FORMAT] [--file /tmp/out]", Safe: true}}, suggestions["PluginMetadata.Commands[0].Usage"])
+	assert.Equal(t, []Suggestion{{Path: "PluginMetadata.Commands[0].Flags[0].Name", Value: "output", Safe: true}}, suggestions["PluginMetadata.Commands[0].Flags[0].Name"])
+	assert.Equal(t, []Suggestion{{Path: "PluginMetadata.Commands[1].Name", Value: "instance-delete"}}, suggestions["PluginMetadata.Commands[1].Name"])
+	assert.Equal(t, []Suggestion{{Path: "PluginMetadata.Commands[1].Description", Value: "Deletes a demo instance."}}, suggestions["PluginMetadata.Commands[1].Description"])
+	assert.Equal(t, []Suggestion{{Path: "PluginMetadata.Commands[1].Usage", Value: "ibmcloud demo instance-rm INSTANCE [--force]"}}, suggestions["PluginMetadata.Commands[1].Usage"])
+}
+
+func TestPluralNameSuggestions(t *testing.T) {
+	metadata := fixableMetadata()
+	metadata.Commands = append(metadata.Commands,
+		Command{Namespace: "demo", Name: "instance-keys", Description: "Creates an instance key.", Usage: "ibmcloud demo instance-keys"},
+		Command{Namespace: "demo", Name: "policies", Description: "Assigns policies.", Usage: "ibmcloud demo policies"},
+	)
+	suggestions := suggestionsByPath(metadata)
+
+	if assert.Len(t, suggestions["PluginMetadata.Commands[2].Name"], 1) {
+		s := suggestions["PluginMetadata.Commands[2].Name"][0]
+		assert.Equal(t, "instance-key", s.Value)
+		assert.False(t, s.Safe)
+		assert.Contains(t, s.Hint, "'instance-key'")
+	}
+	if assert.Len(t, suggestions["PluginMetadata.Commands[3].Name"], 1) {
+		assert.Equal(t, "policy", suggestions["PluginMetadata.Commands[3].Name"][0].Value)
+	}
+	// the description is not rewritten, the metadata does not tell whether the command lists items
+	assert.Empty(t, suggestions["PluginMetadata.Commands[2].Description"])
+
+	fixed, _, err := FixMetadata(metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, "instance-keys", fixed.Commands[2].Name)
+}
+
+func TestSingularName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"instances":     "instance",
+		"instance-keys": "instance-key",
+		"policies":      "policy",
+		"entries":       "entry",
+		"boxes":         "box",
+		"branches":      "branch",
+		"access":        "",
+	} {
+		assert.Equal(t, expected, singularName(name), name)
+	}
+}
+
+func TestApplySuggestions(t *testing.T) {
+	metadata := fixableMetadata()
+	errs := []PluginMetadataError{
+		{Suggestion: &Suggestion{Path: "PluginMetadata.Commands[0].Description", Value: "Show a demo resource.", Safe: true}},
+		{Suggestion: &Suggestion{Path: "PluginMetadata.Commands[0].Description", Value: "Ignored.", Safe: true}},
+		{Suggestion: &Suggestion{Path: "PluginMetadata.Commands[0].Flags[0].Name", Value: "output", Safe: true}},
+		{Suggestion: &Suggestion{Path: "PluginMetadata.MinCliVersion", Value: "2.0.0", Safe: true}},
+		{Suggestion: &Suggestion{Path: "PluginMetadata.Name", Value: "demo-service"}},
+		{},
+	}
+
+	fixed, diff, err := ApplySuggestions(metadata, errs)
+	assert.NoError(t, err)
+	assert.Equal(t, MetadataDiff{
+		{Path: "PluginMetadata.Commands[0].Description", Old: "show a demo resource.", New: "Show a demo resource."},
+		{Path: "PluginMetadata.Commands[0].Flags[0].Name", Old: "<output>", New: "output"},
+		{Path: "PluginMetadata.MinCliVersion", Old: "1.5.0", New: "2.0.0"},
+	}, diff)
+	assert.Equal(t, "Show a demo resource.", fixed.Commands[0].Description)
+	assert.Equal(t, "output", fixed.Commands[0].Flags[0].Name)
+	assert.Equal(t, VersionType{Major: 2}, fixed.MinCliVersion)
+	assert.Equal(t, "DemoService", fixed.Name)
+	assert.Equal(t, fixableMetadata(), metadata, "the original metadata must not be modified")
+
+	assert.Equal(t, "PluginMetadata.MinCliVersion\n- 1.5.0\n+ 2.0.0\n", diff[2:].String())
+}
+
+func TestApplySuggestionsInvalidPath(t *testing.T) {
+	for _, path := range []string{"Commands[0].Name", "PluginMetadata.Commands[5].Name", "PluginMetadata.Unknown", "PluginMetadata.Commands[0].Hidden"} {
+		_, _, err := ApplySuggestions(fixableMetadata(), []PluginMetadataError{{Suggestion: &Suggestion{Path: path, Safe: true}}})
+		assert.Error(t, err, path)
+	}
+}
+
+func TestFixMetadata(t *testing.T) {
+	metadata := fixableMetadata()
+	metadata.Commands[0].Description = "this command shows a demo resource."
+
+	fixed, diff, err := FixMetadata(metadata)
+	assert.NoError(t, err)
+	// the subject is not removed as it is not a safe fix, but the description is capitalized
+	assert.Equal(t, "This command shows a demo resource.", fixed.Commands[0].Description)
+	assert.Equal(t, "ibmcloud demo show NAME [--output FORMAT] [--file /tmp/out]", fixed.Commands[0].Usage)
+	// the minimum CLI version is not raised as it drops the support of older CLIs
+	assert.Equal(t, VersionType{Major: 1, Minor: 5}, fixed.MinCliVersion)
+	assert.Len(t, diff, 3)
+
+	_, diff, err = FixMetadata(fixed)
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
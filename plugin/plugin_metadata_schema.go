@@ -0,0 +1,469 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/i18n"
+)
+
+// MetadataSchemaID is the identifier of the JSON Schema of the metadata printed by a plugin
+// for the 'SendMetadata' argument
+const MetadataSchemaID = "https://raw.githubusercontent.com/IBM-Cloud/ibm-cloud-cli-sdk/master/plugin/plugin_metadata.schema.json"
+
+// MetadataSchemaDialect is the JSON Schema dialect of the metadata schema
+const MetadataSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the subset of JSON Schema 2020-12 used to describe the plugin metadata
+type JSONSchema struct {
+	Schema           string                 `json:"$schema,omitempty"`
+	ID               string                 `json:"$id,omitempty"`
+	Ref              string                 `json:"$ref,omitempty"`
+	Title            string                 `json:"title,omitempty"`
+	Description      string                 `json:"description,omitempty"`
+	Type             SchemaType             `json:"type,omitempty"`
+	Properties       map[string]*JSONSchema `json:"properties,omitempty"`
+	Required         []string               `json:"required,omitempty"`
+	Items            *JSONSchema            `json:"items,omitempty"`
+	MinItems         *int                   `json:"minItems,omitempty"`
+	MinLength        *int                   `json:"minLength,omitempty"`
+	Minimum          *float64               `json:"minimum,omitempty"`
+	ExclusiveMinimum *float64               `json:"exclusiveMinimum,omitempty"`
+	Const            interface{}            `json:"const,omitempty"`
+	Enum             []interface{}          `json:"enum,omitempty"`
+	Pattern          string                 `json:"pattern,omitempty"`
+	AnyOf            []*JSONSchema          `json:"anyOf,omitempty"`
+	Defs             map[string]*JSONSchema `json:"$defs,omitempty"`
+}
+
+// SchemaType is the list of JSON types allowed by a schema. It is marshaled as a string if it
+// contains a single type.
+type SchemaType []string
+
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaType{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// MetadataSchema returns the JSON Schema of PluginMetadata. It is derived from the types
+// PluginMetadata, Namespace, Command and Flag and their validator tags. Rules implemented by
+// custom validations, such as the command usage and naming conventions, are only checked by
+// PluginMetadataValidate.
+func MetadataSchema() *JSONSchema {
+	defs := map[string]*JSONSchema{}
+	root := structSchema(reflect.TypeOf(PluginMetadata{}), defs)
+	delete(defs, "PluginMetadata")
+
+	root.Schema = MetadataSchemaDialect
+	root.ID = MetadataSchemaID
+	root.Description = "Metadata printed by an IBM Cloud CLI plugin for the 'SendMetadata' argument"
+	root.Defs = defs
+	return root
+}
+
+// MetadataSchemaJSON returns the JSON Schema of PluginMetadata as indented JSON, as published in
+// the file 'plugin/plugin_metadata.schema.json' of the SDK repository
+func MetadataSchemaJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(MetadataSchema()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var stageType = reflect.TypeOf(Stage(""))
+
+func structSchema(t reflect.Type, defs map[string]*JSONSchema) *JSONSchema {
+	s := &JSONSchema{
+		Title:      t.Name(),
+		Type:       SchemaType{"object"},
+		Properties: map[string]*JSONSchema{},
+	}
+	defs[t.Name()] = s
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+
+		prop := typeSchema(field.Type, defs)
+		for _, tag := range strings.Split(field.Tag.Get("validate"), ",") {
+			tagName, param, _ := strings.Cut(tag, "=")
+			switch tagName {
+			case "required":
+				s.Required = append(s.Required, name)
+				switch field.Type.Kind() {
+				case reflect.String:
+					prop.MinLength = intPtr(1)
+				case reflect.Slice:
+					// a nil slice is marshaled as null
+					prop.Type = SchemaType{"array"}
+				}
+			case "min":
+				if n, err := strconv.Atoi(param); err == nil {
+					if field.Type.Kind() == reflect.Slice {
+						prop.MinItems = intPtr(n)
+					} else {
+						prop.MinLength = intPtr(n)
+					}
+				}
+			case "excludesall":
+				prop.Pattern = "^[^" + regexp.QuoteMeta(param) + "]*$"
+			case "mincliversion":
+				if v, err := parseVersionType(param); err == nil {
+					prop.Description = "version " + v.String() + " or higher"
+					prop.AnyOf = versionAtLeastSchemas(v)
+				}
+			case "usage":
+				prop.Description = "usage of the command, starting with the CLI name, e.g. 'ibmcloud my-plugin list [--output FORMAT]'"
+			}
+		}
+		s.Properties[name] = prop
+	}
+	return s
+}
+
+func typeSchema(t reflect.Type, defs map[string]*JSONSchema) *JSONSchema {
+	switch t.Kind() {
+	case reflect.String:
+		if t == stageType {
+			return &JSONSchema{
+				Type: SchemaType{"string"},
+				Enum: []interface{}{"", string(StageExperimental), string(StageBeta), string(StageDeprecated)},
+			}
+		}
+		return &JSONSchema{Type: SchemaType{"string"}}
+	case reflect.Bool:
+		return &JSONSchema{Type: SchemaType{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &JSONSchema{Type: SchemaType{"integer"}}
+	case reflect.Slice:
+		return &JSONSchema{Type: SchemaType{"array", "null"}, Items: typeSchema(t.Elem(), defs)}
+	case reflect.Struct:
+		if _, ok := defs[t.Name()]; !ok {
+			structSchema(t, defs)
+		}
+		return &JSONSchema{Ref: "#/$defs/" + t.Name()}
+	}
+	return &JSONSchema{}
+}
+
+// jsonFieldName returns the name of the field in the JSON document
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// versionAtLeastSchemas returns the alternatives matching a VersionType greater than or equal to v
+func versionAtLeastSchemas(v VersionType) []*JSONSchema {
+	return []*JSONSchema{
+		{
+			Properties: map[string]*JSONSchema{"Major": {ExclusiveMinimum: floatPtr(v.Major)}},
+			Required:   []string{"Major"},
+		},
+		{
+			Properties: map[string]*JSONSchema{"Major": {Const: v.Major}, "Minor": {ExclusiveMinimum: floatPtr(v.Minor)}},
+			Required:   []string{"Major", "Minor"},
+		},
+		{
+			Properties: map[string]*JSONSchema{"Major": {Const: v.Major}, "Minor": {Const: v.Minor}, "Build": {Minimum: floatPtr(v.Build)}},
+			Required:   []string{"Major", "Minor", "Build"},
+		},
+	}
+}
+
+func parseVersionType(s string) (VersionType, error) {
+	var v VersionType
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid version '%s'", s)
+	}
+	for i, target := range []*int{&v.Major, &v.Minor, &v.Build} {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return v, fmt.Errorf("invalid version '%s'", s)
+		}
+		*target = n
+	}
+	return v, nil
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func floatPtr(n int) *float64 {
+	f := float64(n)
+	return &f
+}
+
+// ValidateMetadataJSON validates a raw metadata document, as printed by a plugin for the
+// 'SendMetadata' argument, against MetadataSchema. An error is returned if the document is not
+// valid JSON. The namespace of each returned error is the path of the offending value, e.g.
+// 'PluginMetadata.Commands[0].Name'.
+func ValidateMetadataJSON(data []byte) ([]PluginMetadataError, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid metadata JSON: %v", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid metadata JSON: unexpected data after the top-level value")
+	}
+
+	schema := MetadataSchema()
+	v := &schemaValidator{defs: schema.Defs, root: doc, errs: []PluginMetadataError{}}
+	v.check(schema, doc, "PluginMetadata", true)
+	return v.errs, nil
+}
+
+type schemaValidator struct {
+	defs map[string]*JSONSchema
+	root interface{}
+	errs []PluginMetadataError
+}
+
+var commandPathRegexp = regexp.MustCompile(`^PluginMetadata\.Commands\[(\d+)\]`)
+
+func (v *schemaValidator) addError(path string, msg string) {
+	err := PluginMetadataError{
+		Namespace:   path,
+		Error:       msg,
+		Priority:    PriorityError,
+		Remediation: msg,
+	}
+	if m := commandPathRegexp.FindStringSubmatch(path); m != nil {
+		err.CommandName = v.commandName(m[1])
+	}
+	v.errs = append(v.errs, err)
+}
+
+// commandName returns the full name of the command of the given index, if available
+func (v *schemaValidator) commandName(index string) string {
+	i, _ := strconv.Atoi(index)
+	root, _ := v.root.(map[string]interface{})
+	cmds, _ := root["Commands"].([]interface{})
+	if i >= len(cmds) {
+		return ""
+	}
+	cmd, _ := cmds[i].(map[string]interface{})
+	namespace, _ := cmd["Namespace"].(string)
+	name, _ := cmd["Name"].(string)
+	return strings.TrimSpace(namespace + " " + name)
+}
+
+// check reports whether the value matches the schema, and records the errors if record is true
+func (v *schemaValidator) check(s *JSONSchema, value interface{}, path string, record bool) bool {
+	valid := true
+	fail := func(msg string) {
+		valid = false
+		if record {
+			v.addError(path, msg)
+		}
+	}
+	field := path[strings.LastIndex(path, ".")+1:]
+
+	if s.Ref != "" {
+		def, ok := v.defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			fail(fmt.Sprintf("unresolved schema reference '%s'", s.Ref))
+			return false
+		}
+		if !v.check(def, value, path, record) {
+			return false
+		}
+	}
+
+	if len(s.Type) > 0 && !matchesType(s.Type, value) {
+		fail(i18n.T("{{.Field}} must be of type {{.Type}}", map[string]any{
+			"Field": field,
+			"Type":  strings.Join(s.Type, " or "),
+		}))
+		return false
+	}
+
+	if s.Const != nil && !jsonEqual(s.Const, value) {
+		fail(i18n.T("{{.Field}} must be {{.Value}}", map[string]any{"Field": field, "Value": s.Const}))
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if jsonEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values := make([]string, len(s.Enum))
+			for i, e := range s.Enum {
+				values[i] = fmt.Sprintf("'%v'", e)
+			}
+			fail(i18n.T("{{.Field}} must be one of: {{.Values}}", map[string]any{
+				"Field":  field,
+				"Values": strings.Join(values, ", "),
+			}))
+		}
+	}
+
+	switch value := value.(type) {
+	case string:
+		if s.MinLength != nil && len([]rune(value)) < *s.MinLength {
+			if *s.MinLength == 1 {
+				fail(i18n.T("{{.Field}} is required", map[string]any{"Field": field}))
+			} else {
+				fail(i18n.T("{{.Field}} must contain at least {{.Param}} characters", map[string]any{"Field": field, "Param": *s.MinLength}))
+			}
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(value) {
+				fail(i18n.T("{{.Field}} does not match the pattern {{.Pattern}}", map[string]any{"Field": field, "Pattern": s.Pattern}))
+			}
+		}
+	case json.Number:
+		n, _ := value.Float64()
+		if s.Minimum != nil && n < *s.Minimum {
+			fail(i18n.T("{{.Field}} must be greater than or equal to {{.Param}}", map[string]any{"Field": field, "Param": *s.Minimum}))
+		}
+		if s.ExclusiveMinimum != nil && n <= *s.ExclusiveMinimum {
+			fail(i18n.T("{{.Field}} must be greater than {{.Param}}", map[string]any{"Field": field, "Param": *s.ExclusiveMinimum}))
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(value) < *s.MinItems {
+			fail(i18n.T("{{.Field}} must contain at least {{.Param}} element", map[string]any{"Field": field, "Param": *s.MinItems}))
+		}
+		if s.Items != nil {
+			for i, item := range value {
+				if !v.check(s.Items, item, fmt.Sprintf("%s[%d]", path, i), record) {
+					valid = false
+				}
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				valid = false
+				if record {
+					v.addError(path+"."+name, i18n.T("{{.Field}} is required", map[string]any{"Field": name}))
+				}
+			}
+		}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if propValue, ok := value[name]; ok {
+				if !v.check(s.Properties[name], propValue, path+"."+name, record) {
+					valid = false
+				}
+			}
+		}
+	}
+
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, alt := range s.AnyOf {
+			if v.check(alt, value, path, false) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail(i18n.T("{{.Field}} does not match any of the allowed values: {{.Description}}", map[string]any{
+				"Field":       field,
+				"Description": s.Description,
+			}))
+		}
+	}
+	return valid
+}
+
+func matchesType(types SchemaType, value interface{}) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if value == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(json.Number); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := value.(json.Number); ok {
+				if _, err := n.Int64(); err == nil {
+					return true
+				}
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return true
+			}
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonEqual compares a schema value with a decoded JSON value
+func jsonEqual(expected interface{}, value interface{}) bool {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return false
+		}
+		switch e := expected.(type) {
+		case int:
+			return f == float64(e)
+		case float64:
+			return f == e
+		}
+		return false
+	}
+	return reflect.DeepEqual(expected, value)
+}
@@ -0,0 +1,196 @@
+package plugin
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validSchemaMetadata() PluginMetadata {
+	return fillMetadata(PluginMetadata{
+		Name:          "my-plugin",
+		Version:       VersionType{Major: 1, Minor: 2, Build: 3},
+		MinCliVersion: VersionType{Major: 2, Minor: 0, Build: 0},
+		Namespaces: []Namespace{
+			{Name: "my-plugin", Description: "Manage my resources.", Stage: StageBeta},
+		},
+		Commands: []Command{
+			{
+				Namespace:   "my-plugin",
+				Name:        "list",
+				Description: "List all resources.",
+				Usage:       "ibmcloud my-plugin list [--output FORMAT]",
+				Flags: []Flag{
+					{Name: "output", Description: "Specify output format.", HasValue: true},
+				},
+			},
+		},
+	})
+}
+
+func TestMetadataSchemaFileUpToDate(t *testing.T) {
+	expected, err := MetadataSchemaJSON()
+	assert.NoError(t, err)
+
+	published, err := os.ReadFile("plugin_metadata.schema.json")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(published), "plugin_metadata.schema.json is out of date, regenerate it with MetadataSchemaJSON")
+}
+
+func TestMetadataSchema(t *testing.T) {
+	schema := MetadataSchema()
+
+	assert.Equal(t, MetadataSchemaDialect, schema.Schema)
+	assert.Equal(t, []string{"Name", "Version", "MinCliVersion", "Namespaces", "Commands"}, schema.Required)
+	assert.Equal(t, SchemaType{"array"}, schema.Properties["Commands"].Type)
+	assert.Equal(t, 1, *schema.Properties["Commands"].MinItems)
+	assert.Equal(t, SchemaType{"array", "null"}, schema.Properties["Aliases"].Type)
+	assert.Len(t, schema.Properties["MinCliVersion"].AnyOf, 3)
+
+	assert.Contains(t, schema.Defs, "Namespace")
+	assert.Contains(t, schema.Defs, "Command")
+	assert.Contains(t, schema.Defs, "Flag")
+	assert.Contains(t, schema.Defs, "VersionType")
+	assert.NotContains(t, schema.Defs, "PluginMetadata")
+	assert.Equal(t, "^[^<>]*$", schema.Defs["Flag"].Properties["Name"].Pattern)
+	assert.Equal(t, []string{"Namespace", "Name", "Description", "Usage"}, schema.Defs["Command"].Required)
+}
+
+func TestMetadataSchemaRoundTrip(t *testing.T) {
+	data, err := MetadataSchemaJSON()
+	assert.NoError(t, err)
+
+	var schema JSONSchema
+	assert.NoError(t, json.Unmarshal(data, &schema))
+	assert.Equal(t, SchemaType{"object"}, schema.Type)
+	assert.Equal(t, SchemaType{"array", "null"}, schema.Properties["Aliases"].Type)
+}
+
+func TestValidateMetadataJSON(t *testing.T) {
+	data, _ := json.Marshal(validSchemaMetadata())
+
+	errs, err := ValidateMetadataJSON(data)
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestValidateMetadataJSONInvalidDocument(t *testing.T) {
+	_, err := ValidateMetadataJSON([]byte(`{"Name": `))
+	assert.Error(t, err)
+
+	_, err = ValidateMetadataJSON([]byte(`{} {}`))
+	assert.Error(t, err)
+}
+
+func TestValidateMetadataJSONErrors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		modify    func(m map[string]interface{})
+		namespace string
+		command   string
+		error     string
+	}{
+		{
+			name:      "missing name",
+			modify:    func(m map[string]interface{}) { delete(m, "Name") },
+			namespace: "PluginMetadata.Name",
+			error:     "Name is required",
+		},
+		{
+			name:      "empty name",
+			modify:    func(m map[string]interface{}) { m["Name"] = "" },
+			namespace: "PluginMetadata.Name",
+			error:     "Name is required",
+		},
+		{
+			name:      "null commands",
+			modify:    func(m map[string]interface{}) { m["Commands"] = nil },
+			namespace: "PluginMetadata.Commands",
+			error:     "Commands must be of type array",
+		},
+		{
+			name:      "no commands",
+			modify:    func(m map[string]interface{}) { m["Commands"] = []interface{}{} },
+			namespace: "PluginMetadata.Commands",
+			error:     "Commands must contain at least 1 element",
+		},
+		{
+			name:      "null aliases",
+			modify:    func(m map[string]interface{}) { m["Aliases"] = nil },
+			namespace: "",
+		},
+		{
+			name:      "wrong type",
+			modify:    func(m map[string]interface{}) { m["IsCobraPlugin"] = "yes" },
+			namespace: "PluginMetadata.IsCobraPlugin",
+			error:     "IsCobraPlugin must be of type boolean",
+		},
+		{
+			name: "MinCliVersion too low",
+			modify: func(m map[string]interface{}) {
+				m["MinCliVersion"] = map[string]interface{}{"Major": 1, "Minor": 9, "Build": 0}
+			},
+			namespace: "PluginMetadata.MinCliVersion",
+			error:     "MinCliVersion does not match any of the allowed values: version 2.0.0 or higher",
+		},
+		{
+			name: "MinCliVersion higher minor",
+			modify: func(m map[string]interface{}) {
+				m["MinCliVersion"] = map[string]interface{}{"Major": 2, "Minor": 1, "Build": 0}
+			},
+			namespace: "",
+		},
+		{
+			name: "invalid stage",
+			modify: func(m map[string]interface{}) {
+				m["Namespaces"].([]interface{})[0].(map[string]interface{})["Stage"] = "alpha"
+			},
+			namespace: "PluginMetadata.Namespaces[0].Stage",
+			error:     "Stage must be one of: '', 'experimental', 'beta', 'deprecated'",
+		},
+		{
+			name: "missing usage",
+			modify: func(m map[string]interface{}) {
+				delete(m["Commands"].([]interface{})[0].(map[string]interface{}), "Usage")
+			},
+			namespace: "PluginMetadata.Commands[0].Usage",
+			command:   "my-plugin list",
+			error:     "Usage is required",
+		},
+		{
+			name: "forbidden flag characters",
+			modify: func(m map[string]interface{}) {
+				cmd := m["Commands"].([]interface{})[0].(map[string]interface{})
+				cmd["Flags"].([]interface{})[0].(map[string]interface{})["Name"] = "<output>"
+			},
+			namespace: "PluginMetadata.Commands[0].Flags[0].Name",
+			command:   "my-plugin list",
+			error:     "Name does not match the pattern ^[^<>]*$",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, _ := json.Marshal(validSchemaMetadata())
+			var doc map[string]interface{}
+			assert.NoError(t, json.Unmarshal(data, &doc))
+			tc.modify(doc)
+			data, _ = json.Marshal(doc)
+
+			errs, err := ValidateMetadataJSON(data)
+			assert.NoError(t, err)
+			if tc.namespace == "" {
+				assert.Empty(t, errs)
+				return
+			}
+			if assert.Len(t, errs, 1) {
+				assert.Equal(t, tc.namespace, errs[0].Namespace)
+				assert.Equal(t, tc.command, errs[0].CommandName)
+				assert.Equal(t, tc.error, errs[0].Error)
+				assert.Equal(t, PriorityError, errs[0].Priority)
+			}
+		})
+	}
+}
@@ -76,6 +76,9 @@ type PluginMetadataError struct {
 	Error       string   `json:"error"`
 	Priority    Priority `json:"priority"`
 	Remediation string   `json:"remediation,omitempty"`
+
+	// Suggestion is the proposed fix of the error, if any. See ApplySuggestions.
+	Suggestion *Suggestion `json:"suggestion,omitempty"`
 }
 
 type pluginMetadataValidate struct {
@@ -154,6 +157,7 @@ func (p pluginMetadataValidate) Errors(metadatas []PluginMetadata) PluginToValid
 						validErr.Priority = pluginErr.Priority
 					}
 					validErr.Remediation = pluginErr.Remediation
+					validErr.Suggestion = suggestFix(v, cmdName)
 					metadataErrs = append(metadataErrs, validErr)
 				}
 			}
@@ -570,6 +574,7 @@ func (p pluginMetadataValidate) validatePluginInfo(metadata PluginMetadata) []Pl
 			Error:       fmt.Sprintf("Plugin name '%s' contains uppercase letters. Use lowercase with hyphens.", metadata.Name),
 			Priority:    PriorityError,
 			Remediation: "Convert plugin name to lowercase with hyphens (e.g., 'my-service').",
+			Suggestion:  &Suggestion{Path: "PluginMetadata.Name", Value: suggestPluginName(metadata.Name)},
 		})
 	}
 
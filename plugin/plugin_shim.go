@@ -2,14 +2,54 @@ package plugin
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix"
 	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/configuration/config_helpers"
 	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/configuration/core_config"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/terminal"
 	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/i18n"
 )
 
+// HandshakeProtocolVersion is the latest version of the host/plugin protocol supported by the SDK
+const HandshakeProtocolVersion = 1
+
+// Capability is an optional feature of the host/plugin protocol
+type Capability string
+
+// Capabilities known by the SDK
+const (
+	// CapabilityCompletion means the plugin handles the completion of its commands, see PluginMetadata.DelegateBashCompletion and Completer
+	CapabilityCompletion Capability = "completion"
+	// CapabilityPrivateEndpoint means the plugin supports private endpoints, see PluginMetadata.PrivateEndpointSupported
+	CapabilityPrivateEndpoint Capability = "private-endpoint"
+	// CapabilityVPCAccess means the plugin supports private endpoint access via VPC, see PluginMetadata.IsAccessFromVPC
+	CapabilityVPCAccess Capability = "vpc-access"
+	// CapabilityMCP means the plugin can run while the CLI is functioning as an MCP server
+	CapabilityMCP Capability = "mcp"
+)
+
+// HandshakeRequest is sent by the host in the environment variable 'IBMCLOUD_PLUGIN_HANDSHAKE'
+// when it runs the plugin with the 'SendMetadata' argument
+type HandshakeRequest struct {
+	ProtocolVersion int          // latest protocol version supported by the host
+	Features        []Capability // optional features supported by the host
+}
+
+// HandshakeResponse is printed by the plugin instead of the bare metadata in reply to a HandshakeRequest
+type HandshakeResponse struct {
+	ProtocolVersion int            // negotiated protocol version, the lowest of the host and plugin versions
+	Capabilities    []Capability   // capabilities supported by both the plugin and the host
+	Metadata        PluginMetadata // metadata of the plugin
+}
+
+// CapabilityProvider can be implemented by a Plugin to advertise capabilities in addition to the
+// ones derived from its metadata
+type CapabilityProvider interface {
+	Capabilities() []Capability
+}
+
 // Start starts the plugin.
 func Start(plugin Plugin) {
 	StartWithArgs(plugin, os.Args[1:])
@@ -19,7 +59,14 @@ func Start(plugin Plugin) {
 func StartWithArgs(plugin Plugin, args []string) {
 	if isMetadataRequest(args) {
 		metadata := fillMetadata(plugin.GetMetadata())
-		json, err := json.Marshal(metadata)
+
+		var reply interface{} = metadata
+		// hosts which do not send a handshake request get the bare metadata
+		if req, ok := handshakeRequest(); ok {
+			reply = NewHandshakeResponse(plugin, metadata, req)
+		}
+
+		json, err := json.Marshal(reply)
 		if err != nil {
 			panic(err)
 		}
@@ -30,11 +77,22 @@ func StartWithArgs(plugin Plugin, args []string) {
 		return
 	}
 
-	context := InitPluginContext(plugin.GetMetadata().Name)
+	// plugins which do not implement Completer handle the completion requests in Run
+	if _, ok := plugin.(Completer); ok && isCompletionRequest(args) {
+		for _, candidate := range completeCommand(plugin, args[1:]) {
+			fmt.Fprintln(os.Stdout, candidate)
+		}
+		return
+	}
+
+	metadata := plugin.GetMetadata()
+	context := InitPluginContext(metadata.Name)
 
 	// initialization
 	i18n.T = i18n.MustTfunc(context.Locale())
 
+	warnCommandStage(terminal.NewStdUI(), context, metadata, args)
+
 	plugin.Run(context, args)
 }
 
@@ -48,6 +106,96 @@ func fillMetadata(metadata PluginMetadata) PluginMetadata {
 	return metadata
 }
 
+// NewHandshakeResponse negotiates the protocol version and the capabilities of the plugin with
+// the host request
+func NewHandshakeResponse(plugin Plugin, metadata PluginMetadata, req HandshakeRequest) HandshakeResponse {
+	version := HandshakeProtocolVersion
+	if req.ProtocolVersion < version {
+		version = req.ProtocolVersion
+	}
+
+	hostFeatures := make(map[Capability]bool, len(req.Features))
+	for _, f := range req.Features {
+		hostFeatures[f] = true
+	}
+	capabilities := []Capability{}
+	for _, c := range pluginCapabilities(plugin, metadata) {
+		if hostFeatures[c] {
+			capabilities = append(capabilities, c)
+		}
+	}
+
+	return HandshakeResponse{
+		ProtocolVersion: version,
+		Capabilities:    capabilities,
+		Metadata:        metadata,
+	}
+}
+
+// pluginCapabilities returns the capabilities of the plugin without duplicates
+func pluginCapabilities(plugin Plugin, metadata PluginMetadata) []Capability {
+	var capabilities []Capability
+	if _, ok := plugin.(Completer); ok || metadata.DelegateBashCompletion {
+		capabilities = append(capabilities, CapabilityCompletion)
+	}
+	if metadata.PrivateEndpointSupported {
+		capabilities = append(capabilities, CapabilityPrivateEndpoint)
+	}
+	if metadata.IsAccessFromVPC {
+		capabilities = append(capabilities, CapabilityVPCAccess)
+	}
+	if provider, ok := plugin.(CapabilityProvider); ok {
+		capabilities = append(capabilities, provider.Capabilities()...)
+	}
+
+	seen := make(map[Capability]bool, len(capabilities))
+	ret := []Capability{}
+	for _, c := range capabilities {
+		if !seen[c] {
+			seen[c] = true
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// handshakeRequest returns the handshake request sent by the host. An invalid request is ignored
+// so that the host falls back to the bare metadata.
+func handshakeRequest() (HandshakeRequest, bool) {
+	var req HandshakeRequest
+	raw := bluemix.EnvPluginHandshake.Get()
+	if raw == "" {
+		return req, false
+	}
+	if err := json.Unmarshal([]byte(raw), &req); err != nil || req.ProtocolVersion < 1 {
+		return req, false
+	}
+	return req, true
+}
+
+// ParseHandshakeResponse parses the output of a plugin run with the 'SendMetadata' argument.
+// Plugins built with an SDK which does not support the handshake print the bare metadata, in
+// which case the returned protocol version is 0 and there are no capabilities.
+func ParseHandshakeResponse(data []byte) (HandshakeResponse, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return HandshakeResponse{}, fmt.Errorf("invalid plugin metadata: %v", err)
+	}
+
+	var resp HandshakeResponse
+	if _, ok := fields["ProtocolVersion"]; ok {
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return HandshakeResponse{}, fmt.Errorf("invalid plugin handshake response: %v", err)
+		}
+		return resp, nil
+	}
+
+	if err := json.Unmarshal(data, &resp.Metadata); err != nil {
+		return HandshakeResponse{}, fmt.Errorf("invalid plugin metadata: %v", err)
+	}
+	return resp, nil
+}
+
 // InitPluginContext initializes a plugin context for a given plugin
 func InitPluginContext(pluginName string) PluginContext {
 	coreConfig := core_config.NewCoreConfig(
@@ -115,3 +115,83 @@ func TestStartWithArgsWithUrfaveCommand(t *testing.T) {
 	assert.Equal(t, pl.metadata, string(stdoutMockOut))
 
 }
+
+type capabilityTestPlugin struct {
+	metadata PluginMetadata
+}
+
+func (p *capabilityTestPlugin) GetMetadata() PluginMetadata              { return p.metadata }
+func (p *capabilityTestPlugin) Run(context PluginContext, args []string) {}
+func (p *capabilityTestPlugin) Capabilities() []Capability {
+	return []Capability{CapabilityMCP, CapabilityCompletion, "unknown"}
+}
+
+func runSendMetadata(pl Plugin) string {
+	orgStdout := os.Stdout
+	stdoutMock := testhelpers.CreateMockStdout()
+	stdoutFile := stdoutMock.File
+
+	defer func() {
+		os.Stdout = orgStdout
+		os.RemoveAll(stdoutFile.Name())
+		stdoutFile.Close()
+	}()
+
+	os.Stdout = stdoutFile
+	StartWithArgs(pl, []string{"SendMetadata"})
+	return stdoutMock.Read()
+}
+
+func TestStartWithArgsHandshake(t *testing.T) {
+	t.Setenv("IBMCLOUD_PLUGIN_HANDSHAKE", `{"ProtocolVersion": 5, "Features": ["completion", "vpc-access", "mcp"]}`)
+
+	pl := &capabilityTestPlugin{metadata: PluginMetadata{
+		Name:                     "test",
+		DelegateBashCompletion:   true,
+		PrivateEndpointSupported: true,
+		IsAccessFromVPC:          true,
+	}}
+	out := runSendMetadata(pl)
+
+	resp, err := ParseHandshakeResponse([]byte(out))
+	assert.NoError(t, err)
+	assert.Equal(t, HandshakeProtocolVersion, resp.ProtocolVersion)
+	assert.Equal(t, []Capability{CapabilityCompletion, CapabilityVPCAccess, CapabilityMCP}, resp.Capabilities)
+	assert.Equal(t, "test", resp.Metadata.Name)
+	assert.Equal(t, fillMetadata(pl.metadata).SDKVersion, resp.Metadata.SDKVersion)
+}
+
+func TestStartWithArgsHandshakeFallback(t *testing.T) {
+	pl := &capabilityTestPlugin{metadata: PluginMetadata{Name: "test", DelegateBashCompletion: true}}
+	expected := marshalMetadata(fillMetadata(pl.metadata))
+
+	for _, request := range []string{"", "not json", `{"ProtocolVersion": 0}`} {
+		t.Run(request, func(t *testing.T) {
+			t.Setenv("IBMCLOUD_PLUGIN_HANDSHAKE", request)
+			assert.Equal(t, expected, runSendMetadata(pl))
+		})
+	}
+}
+
+func TestNewHandshakeResponse(t *testing.T) {
+	pl := &urfaveTestPlugin{}
+	metadata := PluginMetadata{Name: "test", PrivateEndpointSupported: true}
+
+	resp := NewHandshakeResponse(pl, metadata, HandshakeRequest{ProtocolVersion: 1})
+	assert.Equal(t, 1, resp.ProtocolVersion)
+	assert.Equal(t, []Capability{}, resp.Capabilities)
+
+	resp = NewHandshakeResponse(pl, metadata, HandshakeRequest{ProtocolVersion: 1, Features: []Capability{CapabilityPrivateEndpoint}})
+	assert.Equal(t, []Capability{CapabilityPrivateEndpoint}, resp.Capabilities)
+}
+
+func TestParseHandshakeResponseLegacy(t *testing.T) {
+	resp, err := ParseHandshakeResponse([]byte(marshalMetadata(PluginMetadata{Name: "test"})))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.ProtocolVersion)
+	assert.Empty(t, resp.Capabilities)
+	assert.Equal(t, "test", resp.Metadata.Name)
+
+	_, err = ParseHandshakeResponse([]byte("SendMetadata: command not found"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/terminal"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/i18n"
+)
+
+// ResolveCommand returns the command invoked by the arguments, nil if there is none. The
+// namespace is the one parsed by the CLI (see PluginContext.CommandNamespace); if it is empty,
+// the namespaces are resolved from the leading arguments.
+func ResolveCommand(metadata PluginMetadata, namespace string, args []string) *Command {
+	if len(args) == 0 {
+		return nil
+	}
+
+	tree := newCompletionTree(metadata)
+	if namespace != "" {
+		return tree.command(namespace, args[0])
+	}
+
+	for _, word := range args {
+		if strings.HasPrefix(word, "-") {
+			break
+		}
+		if ns, ok := tree.namespace(namespace, word); ok {
+			namespace = ns
+			continue
+		}
+		if cmd := tree.command(namespace, word); cmd != nil {
+			return cmd
+		}
+		break
+	}
+
+	// commands without namespace are run with the command name only
+	for i := range metadata.Commands {
+		if metadata.Commands[i].Namespace != "" {
+			continue
+		}
+		for _, n := range metadata.Commands[i].NameAndAliases() {
+			if n == args[0] {
+				return &metadata.Commands[i]
+			}
+		}
+	}
+	return nil
+}
+
+// CommandStage returns the stage of the command, or the one of the closest namespace of the
+// command if it is not set
+func CommandStage(metadata PluginMetadata, cmd Command) Stage {
+	if cmd.Stage != "" {
+		return cmd.Stage
+	}
+
+	stages := map[string]Stage{}
+	for _, ns := range metadata.Namespaces {
+		stages[qualifiedName(ns.ParentName, ns.Name)] = ns.Stage
+	}
+	words := strings.Fields(cmd.Namespace)
+	for i := len(words); i > 0; i-- {
+		if stage := stages[strings.Join(words[:i], " ")]; stage != "" {
+			return stage
+		}
+	}
+	return ""
+}
+
+// StageWarning returns the localized warning about the stage of the command, or an empty string
+// if the command is stable
+func StageWarning(cliName string, metadata PluginMetadata, cmd Command) string {
+	if cliName == "" {
+		cliName = "ibmcloud"
+	}
+	name := strings.Join(strings.Fields(cliName+" "+cmd.Namespace+" "+cmd.Name), " ")
+
+	switch CommandStage(metadata, cmd) {
+	case StageExperimental:
+		return i18n.T("Command '{{.Command}}' is experimental. It may change or be removed without notice.", map[string]any{
+			"Command": name,
+		})
+	case StageBeta:
+		return i18n.T("Command '{{.Command}}' is in beta. It may change in future releases.", map[string]any{
+			"Command": name,
+		})
+	case StageDeprecated:
+		warning := i18n.T("Command '{{.Command}}' is deprecated.", map[string]any{
+			"Command": name,
+		})
+		if cmd.ReplacedBy != "" {
+			warning += " " + i18n.T("Use '{{.Replacement}}' instead.", map[string]any{
+				"Replacement": strings.Join(strings.Fields(cliName+" "+cmd.ReplacedBy), " "),
+			})
+		}
+		if cmd.RemovalDate != "" {
+			warning += " " + i18n.T("It will be removed on {{.Date}}.", map[string]any{
+				"Date": cmd.RemovalDate,
+			})
+		} else {
+			warning += " " + i18n.T("It will be removed in a future release.")
+		}
+		return warning
+	}
+	return ""
+}
+
+// warnCommandStage warns about the stage of the invoked command, unless the CLI runs in quiet mode
+func warnCommandStage(ui terminal.UI, context PluginContext, metadata PluginMetadata, args []string) {
+	if quiet, _ := strconv.ParseBool(bluemix.EnvQuiet.Get()); quiet {
+		return
+	}
+
+	cmd := ResolveCommand(metadata, context.CommandNamespace(), args)
+	if cmd == nil {
+		return
+	}
+	// Cobra prints its own warning when a deprecated command is run
+	if metadata.IsCobraPlugin && cmd.Stage == StageDeprecated {
+		return
+	}
+
+	if warning := StageWarning(context.CLIName(), metadata, *cmd); warning != "" {
+		ui.Warn("%s", warning)
+	}
+}
@@ -0,0 +1,92 @@
+package plugin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/terminal"
+	"github.com/stretchr/testify/assert"
+)
+
+var stageMetadata = PluginMetadata{
+	Name: "demo",
+	Namespaces: []Namespace{
+		{Name: "demo", Aliases: []string{"dm"}},
+		{ParentName: "demo", Name: "legacy", Stage: StageDeprecated},
+	},
+	Commands: []Command{
+		{Namespace: "demo", Name: "list", Aliases: []string{"ls"}},
+		{Namespace: "demo", Name: "preview", Stage: StageExperimental},
+		{Namespace: "demo", Name: "show", Stage: StageBeta},
+		{Namespace: "demo", Name: "get", Stage: StageDeprecated, ReplacedBy: "demo show", RemovalDate: "2027-01-31"},
+		{Namespace: "demo legacy", Name: "migrate"},
+		{Name: "hello"},
+	},
+}
+
+func TestResolveCommand(t *testing.T) {
+	testCases := []struct {
+		namespace string
+		args      []string
+		expected  string
+	}{
+		{"demo", []string{"ls", "--output", "json"}, "list"},
+		{"demo legacy", []string{"migrate"}, "migrate"},
+		{"", []string{"dm", "legacy", "migrate", "NAME"}, "migrate"},
+		{"", []string{"demo", "show"}, "show"},
+		{"", []string{"hello"}, "hello"},
+		// commands of a namespace are not resolved without it
+		{"", []string{"list"}, ""},
+		{"demo", []string{"unknown"}, ""},
+		{"", []string{"--help"}, ""},
+		{"", []string{}, ""},
+	}
+
+	for _, tc := range testCases {
+		cmd := ResolveCommand(stageMetadata, tc.namespace, tc.args)
+		if tc.expected == "" {
+			assert.Nil(t, cmd, tc.args)
+		} else if assert.NotNil(t, cmd, tc.args) {
+			assert.Equal(t, tc.expected, cmd.Name)
+		}
+	}
+}
+
+func TestCommandStage(t *testing.T) {
+	assert.Equal(t, Stage(""), CommandStage(stageMetadata, stageMetadata.Commands[0]))
+	assert.Equal(t, StageBeta, CommandStage(stageMetadata, stageMetadata.Commands[2]))
+	// commands inherit the stage of their namespace
+	assert.Equal(t, StageDeprecated, CommandStage(stageMetadata, stageMetadata.Commands[4]))
+}
+
+func TestStageWarning(t *testing.T) {
+	assert.Equal(t, "", StageWarning("ibmcloud", stageMetadata, stageMetadata.Commands[0]))
+	assert.Equal(t, "Command 'ibmcloud demo preview' is experimental. It may change or be removed without notice.", StageWarning("ibmcloud", stageMetadata, stageMetadata.Commands[1]))
+	assert.Equal(t, "Command 'ic demo show' is in beta. It may change in future releases.", StageWarning("ic", stageMetadata, stageMetadata.Commands[2]))
+	assert.Equal(t, "Command 'ibmcloud demo get' is deprecated. Use 'ibmcloud demo show' instead. It will be removed on 2027-01-31.", StageWarning("", stageMetadata, stageMetadata.Commands[3]))
+	assert.Equal(t, "Command 'ibmcloud demo legacy migrate' is deprecated. It will be removed in a future release.", StageWarning("ibmcloud", stageMetadata, stageMetadata.Commands[4]))
+}
+
+func TestWarnCommandStage(t *testing.T) {
+	t.Setenv("IBMCLOUD_HOME", t.TempDir())
+	t.Setenv("IBMCLOUD_CLI", "ibmcloud")
+	t.Setenv("IBMCLOUD_PLUGIN_NAMESPACE", "demo")
+	context := InitPluginContext("demo")
+
+	var out, errOut bytes.Buffer
+	warnCommandStage(terminal.NewUI(&bytes.Buffer{}, &out, &errOut), context, stageMetadata, []string{"show", "NAME"})
+	assert.Contains(t, errOut.String(), "Command 'ibmcloud demo show' is in beta.")
+	assert.Empty(t, out.String())
+
+	errOut.Reset()
+	warnCommandStage(terminal.NewUI(&bytes.Buffer{}, &out, &errOut), context, stageMetadata, []string{"list"})
+	assert.Empty(t, errOut.String())
+
+	// the quiet mode of the UI passed in is left untouched
+	ui := terminal.NewUI(&bytes.Buffer{}, &out, &errOut)
+	t.Setenv("IBMCLOUD_QUIET", "true")
+	warnCommandStage(ui, context, stageMetadata, []string{"show", "NAME"})
+	assert.Empty(t, errOut.String())
+	ui.Warn("warning")
+	assert.Contains(t, errOut.String(), "warning")
+}
@@ -0,0 +1,113 @@
+// Package urfave converts the flags and commands of plug-ins built with the urfave/cli framework
+// to plug-in metadata. It is a separate module so that plug-ins which do not use urfave/cli do
+// not depend on it.
+package urfave
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin"
+	"github.com/urfave/cli"
+)
+
+// ConvertFlagsToPluginFlags will convert flags defined by the urfave/cli framework to Plugin Flags
+// Method is used when defining the Flags in command metadata. @see Plugin#GetMetadata() for use case
+func ConvertFlagsToPluginFlags(flags []cli.Flag) []plugin.Flag {
+	var ret []plugin.Flag
+	for _, f := range flags {
+		hasValue := true
+		var description string
+		if docFlag, ok := f.(cli.DocGenerationFlag); ok {
+			hasValue = docFlag.TakesValue()
+			description = docFlag.GetUsage()
+		}
+
+		ret = append(ret, plugin.Flag{
+			Name:        strings.Join(urfaveFlagNames(f), ","),
+			Description: description,
+			HasValue:    hasValue,
+			Hidden:      urfaveFlagHidden(f),
+		})
+	}
+	return ret
+}
+
+// ConvertCommandsToPluginCommands will convert commands defined by the urfave/cli framework
+// to Plugin Commands of the namespace. The sub commands of a command are converted to commands of
+// the namespace named after the command, e.g. 'demo legacy' for the sub commands of 'legacy'.
+// The usage is the UsageText of the command if it is set, otherwise it is built from the command
+// name, the ArgsUsage and the flags.
+func ConvertCommandsToPluginCommands(namespace string, commands []cli.Command) []plugin.Command {
+	var ret []plugin.Command
+	for _, c := range commands {
+		if len(c.Subcommands) > 0 {
+			ret = append(ret, ConvertCommandsToPluginCommands(strings.TrimSpace(namespace+" "+c.Name), c.Subcommands)...)
+			continue
+		}
+
+		aliases := c.Aliases
+		if c.ShortName != "" {
+			aliases = append([]string{c.ShortName}, aliases...)
+		}
+		flags := ConvertFlagsToPluginFlags(c.Flags)
+
+		usage := c.UsageText
+		if usage == "" {
+			words := append([]string{"ibmcloud", namespace, c.Name}, strings.Fields(c.ArgsUsage)...)
+			for i, f := range c.Flags {
+				words = append(words, plugin.FlagUsage(urfaveFlagNames(f), flags[i].HasValue, urfaveValueName(flags[i].Description)))
+			}
+			usage = strings.Join(strings.Fields(strings.Join(words, " ")), " ")
+		}
+
+		ret = append(ret, plugin.Command{
+			Namespace:   namespace,
+			Name:        c.Name,
+			Aliases:     aliases,
+			Description: c.Usage,
+			Usage:       usage,
+			Flags:       flags,
+			Hidden:      c.Hidden,
+		})
+	}
+	return ret
+}
+
+// urfaveFlagNames returns the names of the flag, such as 'q' and 'quiet' for 'quiet, q', the
+// short names first
+func urfaveFlagNames(f cli.Flag) []string {
+	var short, long []string
+	for _, n := range strings.Split(f.GetName(), ",") {
+		n = strings.TrimSpace(n)
+		switch {
+		case n == "":
+		case len(n) == 1:
+			short = append(short, n)
+		default:
+			long = append(long, n)
+		}
+	}
+	return append(short, long...)
+}
+
+// urfaveFlagHidden returns the Hidden field of the flag, which is defined by each flag type
+func urfaveFlagHidden(f cli.Flag) bool {
+	v := reflect.Indirect(reflect.ValueOf(f))
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+	hidden := v.FieldByName("Hidden")
+	return hidden.IsValid() && hidden.Kind() == reflect.Bool && hidden.Bool()
+}
+
+// urfaveValueName returns the back quoted name of the flag usage, e.g. 'FORMAT' for
+// 'Specify the output `FORMAT`', like urfave/cli does in the help
+func urfaveValueName(usage string) string {
+	if start := strings.Index(usage, "`"); start >= 0 {
+		if end := strings.Index(usage[start+1:], "`"); end > 0 {
+			return usage[start+1 : start+1+end]
+		}
+	}
+	return "VALUE"
+}
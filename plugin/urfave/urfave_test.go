@@ -0,0 +1,79 @@
+package urfave
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/plugin"
+)
+
+func TestConvertFlagsToPluginFlags(t *testing.T) {
+	flags := ConvertFlagsToPluginFlags([]cli.Flag{
+		cli.StringFlag{Name: "output", Usage: "Specify the output `FORMAT`"},
+		cli.BoolFlag{Name: "quiet, q", Usage: "Suppress verbose output"},
+		&cli.IntFlag{Name: "debug-level", Usage: "Internal debugging", Hidden: true},
+	})
+
+	assert.Equal(t, []plugin.Flag{
+		{Name: "output", Description: "Specify the output `FORMAT`", HasValue: true},
+		{Name: "q,quiet", Description: "Suppress verbose output"},
+		{Name: "debug-level", Description: "Internal debugging", HasValue: true, Hidden: true},
+	}, flags)
+}
+
+func TestConvertCommandsToPluginCommands(t *testing.T) {
+	commands := ConvertCommandsToPluginCommands("demo", []cli.Command{
+		{
+			Name:      "list",
+			ShortName: "l",
+			Aliases:   []string{"ls"},
+			Usage:     "List all demo resources.",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "output", Usage: "Specify the output `FORMAT`"},
+				cli.BoolFlag{Name: "q, quiet", Usage: "Suppress verbose output"},
+			},
+		},
+		{
+			Name:      "show",
+			Usage:     "Show a demo resource.",
+			UsageText: "ibmcloud demo show NAME",
+			Hidden:    true,
+		},
+		{
+			Name:  "legacy",
+			Usage: "Manage legacy resources.",
+			Subcommands: []cli.Command{
+				{Name: "migrate", Usage: "Migrate a legacy resource.", ArgsUsage: "NAME"},
+			},
+		},
+	})
+
+	assert.Equal(t, []plugin.Command{
+		{
+			Namespace:   "demo",
+			Name:        "list",
+			Aliases:     []string{"l", "ls"},
+			Description: "List all demo resources.",
+			Usage:       "ibmcloud demo list [--output FORMAT] [--quiet]",
+			Flags: []plugin.Flag{
+				{Name: "output", Description: "Specify the output `FORMAT`", HasValue: true},
+				{Name: "q,quiet", Description: "Suppress verbose output"},
+			},
+		},
+		{
+			Namespace:   "demo",
+			Name:        "show",
+			Description: "Show a demo resource.",
+			Usage:       "ibmcloud demo show NAME",
+			Hidden:      true,
+		},
+		{
+			Namespace:   "demo legacy",
+			Name:        "migrate",
+			Description: "Migrate a legacy resource.",
+			Usage:       "ibmcloud demo legacy migrate NAME",
+		},
+	}, commands)
+}
@@ -321,10 +321,38 @@ var _i18nResourcesAllEn_usJson = []byte(`[
     "id": "An error occurred while dumping response:\n{{.Error}}\n",
     "translation": "An error occurred while dumping response:\n{{.Error}}\n"
   },
+  {
+    "id": "An error occurred while writing HAR file '{{.Path}}':\n{{.Error}}\n",
+    "translation": "An error occurred while writing HAR file '{{.Path}}':\n{{.Error}}\n"
+  },
+  {
+    "id": "Answer",
+    "translation": "Answer"
+  },
+  {
+    "id": "Approve the login request on your phone, then press Enter to continue",
+    "translation": "Approve the login request on your phone, then press Enter to continue"
+  },
+  {
+    "id": "CURL:",
+    "translation": "CURL:"
+  },
   {
     "id": "Capitalize the first letter of the description.",
     "translation": "Capitalize the first letter of the description."
   },
+  {
+    "id": "Command '{{.Command}}' is deprecated.",
+    "translation": "Command '{{.Command}}' is deprecated."
+  },
+  {
+    "id": "Command '{{.Command}}' is experimental. It may change or be removed without notice.",
+    "translation": "Command '{{.Command}}' is experimental. It may change or be removed without notice."
+  },
+  {
+    "id": "Command '{{.Command}}' is in beta. It may change in future releases.",
+    "translation": "Command '{{.Command}}' is in beta. It may change in future releases."
+  },
   {
     "id": "Command '{{.Name}}' contains a segment '{{.Segment}}' that is less than {{.Count}} characters. Each word in a command should be at least {{.Count}} characters.",
     "translation": "Command '{{.Name}}' contains a segment '{{.Segment}}' that is less than {{.Count}} characters. Each word in a command should be at least {{.Count}} characters."
@@ -365,6 +393,10 @@ var _i18nResourcesAllEn_usJson = []byte(`[
     "id": "Could not read from input: ",
     "translation": "Could not read from input: "
   },
+  {
+    "id": "DNS lookup",
+    "translation": "DNS lookup"
+  },
   {
     "id": "Description",
     "translation": "Description"
@@ -385,6 +417,14 @@ var _i18nResourcesAllEn_usJson = []byte(`[
     "id": "Elapsed:",
     "translation": "Elapsed:"
   },
+  {
+    "id": "Enter the code from your Verisign token",
+    "translation": "Enter the code from your Verisign token"
+  },
+  {
+    "id": "Enter the code from your authenticator app",
+    "translation": "Enter the code from your authenticator app"
+  },
   {
     "id": "External authentication failed. Error code: {{.ErrorCode}}, message: {{.Message}}",
     "translation": "External authentication failed. Error code: {{.ErrorCode}}, message: {{.Message}}"
@@ -413,10 +453,22 @@ var _i18nResourcesAllEn_usJson = []byte(`[
     "id": "Invalid token: ",
     "translation": "Invalid token: "
   },
+  {
+    "id": "It will be removed in a future release.",
+    "translation": "It will be removed in a future release."
+  },
+  {
+    "id": "It will be removed on {{.Date}}.",
+    "translation": "It will be removed on {{.Date}}."
+  },
   {
     "id": "MinCliVersion ({{.ProvidedMinVersion}}) is lower than the allowed minimum {{.AllowedMinimum}}",
     "translation": "MinCliVersion ({{.ProvidedMinVersion}}) is lower than the allowed minimum {{.AllowedMinimum}}"
   },
+  {
+    "id": "Multi-factor authentication failed after {{.Attempts}} attempts: ",
+    "translation": "Multi-factor authentication failed after {{.Attempts}} attempts: "
+  },
   {
     "id": "OK",
     "translation": "OK"
@@ -461,6 +513,18 @@ var _i18nResourcesAllEn_usJson = []byte(`[
     "id": "Remove placeholders from command usage text",
     "translation": "Remove placeholders from command usage text"
   },
+  {
+    "id": "Rename the command to '{{.Name}}' if it acts on a single item, otherwise say in the description that it returns multiple items, e.g. 'List all ...'.",
+    "translation": "Rename the command to '{{.Name}}' if it acts on a single item, otherwise say in the description that it returns multiple items, e.g. 'List all ...'."
+  },
+  {
+    "id": "Say in the description that the command returns multiple items, e.g. 'List all ...'.",
+    "translation": "Say in the description that the command returns multiple items, e.g. 'List all ...'."
+  },
+  {
+    "id": "Security question ID",
+    "translation": "Security question ID"
+  },
   {
     "id": "Session inactive: ",
     "translation": "Session inactive: "
@@ -473,6 +537,18 @@ var _i18nResourcesAllEn_usJson = []byte(`[
     "id": "Shorten the description to be more concise.",
     "translation": "Shorten the description to be more concise."
   },
+  {
+    "id": "TCP connect",
+    "translation": "TCP connect"
+  },
+  {
+    "id": "TLS handshake",
+    "translation": "TLS handshake"
+  },
+  {
+    "id": "Timing:",
+    "translation": "Timing:"
+  },
   {
     "id": "Unable to save plugin config: ",
     "translation": "Unable to save plugin config: "
@@ -493,6 +569,10 @@ var _i18nResourcesAllEn_usJson = []byte(`[
     "id": "Usage does not have any usage text besides COMMAND",
     "translation": "Usage does not have any usage text besides COMMAND"
   },
+  {
+    "id": "Use '{{.Replacement}}' instead.",
+    "translation": "Use '{{.Replacement}}' instead."
+  },
   {
     "id": "Use common verbs in command names such as list, create, update, delete, or use plural forms to indicate listing operations.",
     "translation": "Use common verbs in command names such as list, create, update, delete, or use plural forms to indicate listing operations."
@@ -501,14 +581,58 @@ var _i18nResourcesAllEn_usJson = []byte(`[
     "id": "Use more descriptive words with at least {{.Count}} characters for each segment.",
     "translation": "Use more descriptive words with at least {{.Count}} characters for each segment."
   },
+  {
+    "id": "connection reused",
+    "translation": "connection reused"
+  },
+  {
+    "id": "remote address",
+    "translation": "remote address"
+  },
+  {
+    "id": "time to first byte",
+    "translation": "time to first byte"
+  },
   {
     "id": "{{.Field}} contains the following forbidden characters: {{.Chars}}",
     "translation": "{{.Field}} contains the following forbidden characters: {{.Chars}}"
   },
+  {
+    "id": "{{.Field}} does not match any of the allowed values: {{.Description}}",
+    "translation": "{{.Field}} does not match any of the allowed values: {{.Description}}"
+  },
+  {
+    "id": "{{.Field}} does not match the pattern {{.Pattern}}",
+    "translation": "{{.Field}} does not match the pattern {{.Pattern}}"
+  },
   {
     "id": "{{.Field}} is required",
     "translation": "{{.Field}} is required"
   },
+  {
+    "id": "{{.Field}} must be greater than or equal to {{.Param}}",
+    "translation": "{{.Field}} must be greater than or equal to {{.Param}}"
+  },
+  {
+    "id": "{{.Field}} must be greater than {{.Param}}",
+    "translation": "{{.Field}} must be greater than {{.Param}}"
+  },
+  {
+    "id": "{{.Field}} must be of type {{.Type}}",
+    "translation": "{{.Field}} must be of type {{.Type}}"
+  },
+  {
+    "id": "{{.Field}} must be one of: {{.Values}}",
+    "translation": "{{.Field}} must be one of: {{.Values}}"
+  },
+  {
+    "id": "{{.Field}} must be {{.Value}}",
+    "translation": "{{.Field}} must be {{.Value}}"
+  },
+  {
+    "id": "{{.Field}} must contain at least {{.Param}} characters",
+    "translation": "{{.Field}} must contain at least {{.Param}} characters"
+  },
   {
     "id": "{{.Field}} must contain at least {{.Param}} element",
     "translation": "{{.Field}} must contain at least {{.Param}} element"
@@ -525,7 +649,7 @@ func i18nResourcesAllEn_usJson() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "i18n/resources/all.en_US.json", size: 9044, mode: os.FileMode(420), modTime: time.Unix(1780525928, 0)}
+	info := bindataFileInfo{name: "i18n/resources/all.en_US.json", size: 13033, mode: os.FileMode(420), modTime: time.Unix(1780525928, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -0,0 +1,555 @@
+// Package identity provides a fake IAM token server for testing code built on the iam package.
+package identity
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/authentication"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/authentication/iam"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultClientID     = "bx"
+	defaultClientSecret = "bx"
+	defaultLifetime     = time.Hour
+	keyID               = "fake-iam-key"
+)
+
+// Identity describes the subject a token is issued for. The fields are written to the
+// access token claims that core_config.NewIAMTokenInfo reads.
+type Identity struct {
+	IAMID        string
+	ID           string
+	RealmID      string
+	Identifier   string
+	Firstname    string
+	Lastname     string
+	Name         string
+	Email        string
+	Subject      string
+	SubjectType  string
+	AccountID    string
+	IMSAccountID string
+	IMSUserID    int64
+	// Authn is the authenticated compute resource or user when the subject is a trusted profile
+	Authn *Identity
+}
+
+// MFAChallenge is a second factor the fake server requires before issuing a token for a user.
+// RequirementCode and RequirementMessage are returned in the 'requirements' of the API error.
+type MFAChallenge struct {
+	RequirementCode    string
+	RequirementMessage string
+
+	// Vendor and SecurityCode are expected for TOTP and Verisign challenges
+	Vendor       iam.MFAVendor
+	SecurityCode string
+
+	// SecurityQuestionID and SecurityQuestionAnswer are expected for security question challenges
+	SecurityQuestionID     int
+	SecurityQuestionAnswer string
+}
+
+func (c MFAChallenge) satisfiedBy(v url.Values) bool {
+	if c.SecurityQuestionID != 0 {
+		return v.Get("security_question_id") == strconv.Itoa(c.SecurityQuestionID) &&
+			v.Get("security_question_answer") == c.SecurityQuestionAnswer
+	}
+	if c.Vendor == iam.MFAVendorPhoneFactor {
+		return v.Get("vendor") == c.Vendor.String() && v.Get("authentication_token") != ""
+	}
+	return v.Get("vendor") == c.Vendor.String() && v.Get("security_code") == c.SecurityCode
+}
+
+type user struct {
+	password string
+	identity Identity
+	mfa      *MFAChallenge
+}
+
+type crToken struct {
+	profiles []Identity
+}
+
+type authorizationCode struct {
+	redirectURI string
+	identity    Identity
+}
+
+// Server is a fake IAM token service. It serves the token, session, phone factor and
+// OpenID configuration endpoints used by iam.Interface and issues RS256 signed JWTs.
+type Server struct {
+	*httptest.Server
+
+	key *rsa.PrivateKey
+
+	mu                 sync.Mutex
+	clientID           string
+	clientSecret       string
+	lifetime           time.Duration
+	users              map[string]*user
+	apiKeys            map[string]Identity
+	passcodes          map[string]Identity
+	crTokens           map[string]crToken
+	authorizationCodes map[string]authorizationCode
+	refreshTokens      map[string]Identity
+	expiredRefresh     map[string]bool
+	sessions           map[string]bool
+	phoneTokens        map[string]bool
+	grantErrors        map[authentication.GrantType]*serverError
+	tokenRequests      []url.Values
+}
+
+// NewServer starts a fake IAM server. Use the server URL with iam.DefaultConfig.
+func NewServer() *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(fmt.Errorf("failed to generate signing key for fake IAM server: %v", err))
+	}
+
+	s := &Server{
+		key:                key,
+		clientID:           defaultClientID,
+		clientSecret:       defaultClientSecret,
+		lifetime:           defaultLifetime,
+		users:              make(map[string]*user),
+		apiKeys:            make(map[string]Identity),
+		passcodes:          make(map[string]Identity),
+		crTokens:           make(map[string]crToken),
+		authorizationCodes: make(map[string]authorizationCode),
+		refreshTokens:      make(map[string]Identity),
+		expiredRefresh:     make(map[string]bool),
+		sessions:           make(map[string]bool),
+		phoneTokens:        make(map[string]bool),
+		grantErrors:        make(map[authentication.GrantType]*serverError),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/identity/token", s.handleToken)
+	mux.HandleFunc("/identity/initiate_ims_2fa", s.handleInitiatePhoneFactor)
+	mux.HandleFunc("/identity/.well-known/openid-configuration", s.handleOpenIDConfiguration)
+	mux.HandleFunc("/identity/keys", s.handleKeys)
+	mux.HandleFunc("/v1/sessions/", s.handleSession)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// PublicKey returns the key that verifies the signature of issued tokens
+func (s *Server) PublicKey() *rsa.PublicKey {
+	return &s.key.PublicKey
+}
+
+// SetClientCredentials changes the client ID and secret expected in the basic authorization header
+func (s *Server) SetClientCredentials(clientID, clientSecret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clientID = clientID
+	s.clientSecret = clientSecret
+}
+
+// SetTokenLifetime changes the lifetime of issued access tokens
+func (s *Server) SetTokenLifetime(lifetime time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lifetime = lifetime
+}
+
+// AddUser registers a user for the password grant
+func (s *Server) AddUser(username, password string, identity Identity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[username] = &user{password: password, identity: identity}
+}
+
+// RequireMFA makes the password grant of the user fail with an external authentication
+// error until the request carries the answer to the challenge
+func (s *Server) RequireMFA(username string, challenge MFAChallenge) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u, ok := s.users[username]; ok {
+		u.mfa = &challenge
+	}
+}
+
+// AddAPIKey registers an API key for the apikey grant
+func (s *Server) AddAPIKey(apikey string, identity Identity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKeys[apikey] = identity
+}
+
+// AddPasscode registers a one-time passcode for the passcode grant. The passcode is consumed on use.
+func (s *Server) AddPasscode(passcode string, identity Identity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.passcodes[passcode] = identity
+}
+
+// AddCRToken registers a compute resource token for the cr-token grant together with the trusted
+// profiles it can be exchanged for. Profiles are matched by 'ID', 'Name' or 'IAMID' (as the CRN).
+func (s *Server) AddCRToken(token string, profiles ...Identity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.crTokens[token] = crToken{profiles: profiles}
+}
+
+// AddAuthorizationCode registers a code for the authorization_code grant. The code is consumed on use.
+func (s *Server) AddAuthorizationCode(code, redirectURI string, identity Identity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.authorizationCodes[code] = authorizationCode{redirectURI: redirectURI, identity: identity}
+}
+
+// ExpireRefreshToken makes the refresh_token grant fail with a refresh token expiry error for the token
+func (s *Server) ExpireRefreshToken(refreshToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expiredRefresh[refreshToken] = true
+}
+
+// ExpireSession makes refreshing the session fail with a session inactive error
+func (s *Server) ExpireSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sessionID] = false
+}
+
+// IsSessionActive returns whether the session was issued by the server and has not expired
+func (s *Server) IsSessionActive(sessionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[sessionID]
+}
+
+// FailGrant makes every request of the grant type fail with the API error and status code
+func (s *Server) FailGrant(grantType authentication.GrantType, statusCode int, apiErr iam.APIError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grantErrors[grantType] = &serverError{statusCode: statusCode, apiErr: apiErr}
+}
+
+// TokenRequests returns the form values of all token requests received
+func (s *Server) TokenRequests() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := make([]url.Values, len(s.tokenRequests))
+	copy(ret, s.tokenRequests)
+	return ret
+}
+
+// NewAccessToken signs an access token for the identity without going through a grant
+func (s *Server) NewAccessToken(identity Identity, grantType authentication.GrantType) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sign(s.claims(identity, grantType, uuid.NewString(), time.Now()))
+}
+
+type serverError struct {
+	statusCode int
+	apiErr     iam.APIError
+}
+
+func (e *serverError) Error() string {
+	return e.apiErr.ErrorCode + ": " + e.apiErr.ErrorMessage
+}
+
+func newError(statusCode int, code, message string) *serverError {
+	return &serverError{statusCode: statusCode, apiErr: iam.APIError{ErrorCode: code, ErrorMessage: message}}
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	id, secret, ok := r.BasicAuth()
+	return ok && id == s.clientID && secret == s.clientSecret
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		writeError(w, newError(http.StatusMethodNotAllowed, "BXNIM0101E", "method not allowed"))
+		return
+	}
+	if !s.authorized(r) {
+		writeError(w, newError(http.StatusUnauthorized, "BXNIM0308E", "invalid client credentials"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, newError(http.StatusBadRequest, "BXNIM0109E", err.Error()))
+		return
+	}
+	s.tokenRequests = append(s.tokenRequests, r.PostForm)
+
+	grantType := authentication.GrantType(r.PostForm.Get("grant_type"))
+	if err, ok := s.grantErrors[grantType]; ok {
+		writeError(w, err)
+		return
+	}
+
+	identity, err := s.authenticate(grantType, r.PostForm)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if account := r.PostForm.Get("account"); account != "" {
+		identity.AccountID = account
+	}
+	if imsAccount := r.PostForm.Get("ims_account"); imsAccount != "" {
+		identity.IMSAccountID = imsAccount
+	}
+
+	s.writeToken(w, identity, grantType, r.PostForm.Get("response_type"))
+}
+
+func (s *Server) authenticate(grantType authentication.GrantType, v url.Values) (Identity, *serverError) {
+	switch grantType {
+	case iam.GrantTypePassword:
+		u, ok := s.users[v.Get("username")]
+		if !ok || u.password != v.Get("password") {
+			return Identity{}, newError(http.StatusBadRequest, "BXNIM0602E", "The credentials you entered for the user are incorrect.")
+		}
+		if u.mfa != nil && !u.mfa.satisfiedBy(v) {
+			e := newError(http.StatusBadRequest, iam.ExternalAuthenticationErrorCode, "External authentication failed.")
+			e.apiErr.Requirements = iam.Requirement{ErrorCode: u.mfa.RequirementCode, ErrorMessage: u.mfa.RequirementMessage}
+			return Identity{}, e
+		}
+		if u.mfa != nil && u.mfa.Vendor == iam.MFAVendorPhoneFactor {
+			if !s.phoneTokens[v.Get("authentication_token")] {
+				return Identity{}, newError(http.StatusBadRequest, iam.InvalidTokenErrorCode, "Phone factor authentication token is invalid.")
+			}
+			delete(s.phoneTokens, v.Get("authentication_token"))
+		}
+		return u.identity, nil
+
+	case iam.GrantTypeAPIKey:
+		identity, ok := s.apiKeys[v.Get("apikey")]
+		if !ok {
+			return Identity{}, newError(http.StatusBadRequest, "BXNIM0415E", "Provided API key could not be found.")
+		}
+		return identity, nil
+
+	case iam.GrantTypeOnetimePasscode:
+		identity, ok := s.passcodes[v.Get("passcode")]
+		if !ok {
+			return Identity{}, newError(http.StatusBadRequest, "BXNIM0405E", "Provided passcode is invalid.")
+		}
+		delete(s.passcodes, v.Get("passcode"))
+		return identity, nil
+
+	case iam.GrantTypeCRToken:
+		token, ok := s.crTokens[v.Get("cr_token")]
+		if !ok {
+			return Identity{}, newError(http.StatusBadRequest, iam.InvalidTokenErrorCode, "Provided compute resource token is invalid.")
+		}
+		for _, p := range token.profiles {
+			if (v.Get("profile_id") == "" || v.Get("profile_id") == p.ID) &&
+				(v.Get("profile_name") == "" || v.Get("profile_name") == p.Name) &&
+				(v.Get("profile_crn") == "" || v.Get("profile_crn") == p.IAMID) {
+				return p, nil
+			}
+		}
+		return Identity{}, newError(http.StatusBadRequest, "BXNIM0495E", "The compute resource is not linked to the requested trusted profile.")
+
+	case iam.GrantTypeAuthorizationCode:
+		code, ok := s.authorizationCodes[v.Get("code")]
+		if !ok || code.redirectURI != v.Get("redirect_uri") {
+			return Identity{}, newError(http.StatusBadRequest, "BXNIM0403E", "Provided authorization code is invalid.")
+		}
+		delete(s.authorizationCodes, v.Get("code"))
+		return code.identity, nil
+
+	case iam.GrantTypeRefreshToken:
+		refreshToken := v.Get("refresh_token")
+		if s.expiredRefresh[refreshToken] {
+			return Identity{}, newError(http.StatusBadRequest, iam.RefreshTokenExpiryErrorCode, "Provided refresh token is expired.")
+		}
+		identity, ok := s.refreshTokens[refreshToken]
+		if !ok {
+			return Identity{}, newError(http.StatusBadRequest, iam.InvalidTokenErrorCode, "Provided refresh token is invalid.")
+		}
+		return identity, nil
+	}
+
+	return Identity{}, newError(http.StatusBadRequest, "BXNIM0103E", fmt.Sprintf("Grant type '%s' is not supported.", grantType))
+}
+
+func (s *Server) writeToken(w http.ResponseWriter, identity Identity, grantType authentication.GrantType, responseType string) {
+	now := time.Now()
+	sessionID := "C-" + uuid.NewString()
+	s.sessions[sessionID] = true
+
+	refreshToken := uuid.NewString()
+	s.refreshTokens[refreshToken] = identity
+
+	resp := map[string]interface{}{
+		"access_token":  s.sign(s.claims(identity, grantType, sessionID, now)),
+		"refresh_token": refreshToken,
+		"session_id":    sessionID,
+		"token_type":    "Bearer",
+		"scope":         "ibm openid",
+		"expires_in":    int64(s.lifetime.Seconds()),
+		"expiration":    now.Add(s.lifetime).Unix(),
+	}
+	if strings.Contains(responseType, iam.ResponseTypeIMS.String()) {
+		resp["ims_user_id"] = identity.IMSUserID
+		resp["ims_token"] = uuid.NewString()
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) claims(identity Identity, grantType authentication.GrantType, sessionID string, now time.Time) map[string]interface{} {
+	c := identityClaims(identity)
+	c["session_id"] = sessionID
+	c["jti"] = uuid.NewString()
+	c["iss"] = s.URL + "/identity"
+	c["grant_type"] = grantType.String()
+	c["scope"] = "ibm openid"
+	c["client_id"] = s.clientID
+	c["iat"] = now.Unix()
+	c["exp"] = now.Add(s.lifetime).Unix()
+	c["account"] = map[string]interface{}{
+		"bss":   identity.AccountID,
+		"ims":   identity.IMSAccountID,
+		"valid": identity.AccountID != "",
+	}
+	if identity.Authn != nil {
+		c["authn"] = identityClaims(*identity.Authn)
+	}
+	return c
+}
+
+func identityClaims(identity Identity) map[string]interface{} {
+	c := map[string]interface{}{
+		"iam_id":      identity.IAMID,
+		"id":          identity.ID,
+		"realmid":     identity.RealmID,
+		"identifier":  identity.Identifier,
+		"given_name":  identity.Firstname,
+		"family_name": identity.Lastname,
+		"name":        identity.Name,
+		"email":       identity.Email,
+		"sub":         identity.Subject,
+	}
+	if identity.SubjectType != "" {
+		c["sub_type"] = identity.SubjectType
+	}
+	return c
+}
+
+func (s *Server) sign(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": keyID})
+	payload, _ := json.Marshal(claims)
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(fmt.Errorf("failed to sign token: %v", err))
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (s *Server) handleInitiatePhoneFactor(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.authorized(r) {
+		writeError(w, newError(http.StatusUnauthorized, "BXNIM0308E", "invalid client credentials"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, newError(http.StatusBadRequest, "BXNIM0109E", err.Error()))
+		return
+	}
+
+	u, ok := s.users[r.PostForm.Get("username")]
+	if !ok || u.password != r.PostForm.Get("password") {
+		writeError(w, newError(http.StatusBadRequest, "BXNIM0602E", "The credentials you entered for the user are incorrect."))
+		return
+	}
+
+	token := uuid.NewString()
+	s.phoneTokens[token] = true
+	writeJSON(w, http.StatusOK, map[string]string{"authenticationToken": token})
+}
+
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodPatch || !strings.HasSuffix(r.URL.Path, "/state") {
+		writeError(w, newError(http.StatusNotFound, "BXNIM0102E", "not found"))
+		return
+	}
+	if !s.authorized(r) {
+		writeError(w, newError(http.StatusUnauthorized, "BXNIM0308E", "invalid client credentials"))
+		return
+	}
+
+	sessionID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/sessions/"), "/state")
+	if !s.sessions[sessionID] {
+		writeError(w, newError(http.StatusBadRequest, iam.SessionInactiveErrorCode, "The session is no longer active."))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleOpenIDConfiguration(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.URL + "/identity",
+		"authorization_endpoint": s.URL + "/identity/authorize",
+		"token_endpoint":         s.URL + "/identity/token",
+		"passcode_endpoint":      s.URL + "/identity/passcode",
+		"jwks_uri":               s.URL + "/identity/keys",
+	})
+}
+
+func (s *Server) handleKeys(w http.ResponseWriter, r *http.Request) {
+	pub := s.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(exponentBytes(pub.E)),
+		}},
+	})
+}
+
+// exponentBytes encodes the public exponent as a big-endian byte slice without leading zeros
+func exponentBytes(e int) []byte {
+	var b []byte
+	for ; e > 0; e >>= 8 {
+		b = append([]byte{byte(e)}, b...)
+	}
+	return b
+}
+
+func writeError(w http.ResponseWriter, e *serverError) {
+	writeJSON(w, e.statusCode, e.apiErr)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	// #nosec G104 - the test client going away is not an error of the fake server
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,264 @@
+// Package metadata provides an in-process emulator of the VPC and PowerVS instance
+// metadata services so that compute resource (CR) authentication can be tested offline.
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/authentication/vpc"
+)
+
+const (
+	operationPathCreateAccessToken = "/identity/v1/token"
+	operationPathCreateIamToken    = "/identity/v1/iam_tokens"
+
+	defaultIdentityToken = "mock-instance-identity-token" // #nosec G101 - this is a fake token used for testing. Not a credential
+	defaultIAMToken      = "mock-iam-access-token"        // #nosec G101 - this is a fake token used for testing. Not a credential
+	defaultIAMLifetime   = 3600
+)
+
+// Error is a failure the emulator returns instead of a token
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+// TrustedProfile is the trusted profile received in a create_iam_token request
+type TrustedProfile struct {
+	ID  string `json:"id"`
+	CRN string `json:"crn"`
+}
+
+// Server is an httptest based emulator of the instance metadata service. It serves the
+// "create_access_token" and "create_iam_token" operations used by the vpc package and
+// validates the headers and API version that the VPC or PowerVS variant requires.
+type Server struct {
+	*httptest.Server
+
+	isPVS   bool
+	version string
+
+	mu                 sync.Mutex
+	identityToken      string
+	iamToken           string
+	iamTokenLifetime   int
+	identityTokenErr   *Error
+	iamTokenErr        *Error
+	identityTokenCalls int
+	iamTokenCalls      int
+	lastTrustedProfile *TrustedProfile
+}
+
+// NewVPCServer starts a VPC instance metadata service emulator which requires
+// the 'version' query parameter to equal vpc.DefaultMetadataServiceVersion
+func NewVPCServer() *Server {
+	return newServer(false, vpc.DefaultMetadataServiceVersion)
+}
+
+// NewPowerVSServer starts a PowerVS instance metadata service emulator which requires
+// the Metadata-Flavor header on every operation and rejects the 'version' query parameter
+func NewPowerVSServer() *Server {
+	return newServer(true, "")
+}
+
+func newServer(isPVS bool, version string) *Server {
+	s := &Server{
+		isPVS:            isPVS,
+		version:          version,
+		identityToken:    defaultIdentityToken,
+		iamToken:         defaultIAMToken,
+		iamTokenLifetime: defaultIAMLifetime,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// SetVersion changes the API version the VPC emulator expects. An empty version disables the check.
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// SetIdentityToken sets the instance identity token issued by the create_access_token operation
+func (s *Server) SetIdentityToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identityToken = token
+}
+
+// SetIAMToken sets the IAM access token and its lifetime in seconds issued by the create_iam_token operation
+func (s *Server) SetIAMToken(token string, expiresIn int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.iamToken = token
+	s.iamTokenLifetime = expiresIn
+}
+
+// FailIdentityToken makes the create_access_token operation fail with the given error. Pass nil to reset.
+func (s *Server) FailIdentityToken(err *Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identityTokenErr = err
+}
+
+// FailIAMToken makes the create_iam_token operation fail with the given error. Pass nil to reset.
+func (s *Server) FailIAMToken(err *Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.iamTokenErr = err
+}
+
+// IdentityTokenCallCount returns the number of create_access_token requests received
+func (s *Server) IdentityTokenCallCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.identityTokenCalls
+}
+
+// IAMTokenCallCount returns the number of create_iam_token requests received
+func (s *Server) IAMTokenCallCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.iamTokenCalls
+}
+
+// LastTrustedProfile returns the trusted profile of the last create_iam_token request,
+// or nil if the request did not contain one
+func (s *Server) LastTrustedProfile() *TrustedProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastTrustedProfile
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case operationPathCreateAccessToken:
+		s.identityTokenCalls++
+		s.createAccessToken(w, r)
+	case operationPathCreateIamToken:
+		s.iamTokenCalls++
+		s.createIAMToken(w, r)
+	default:
+		writeError(w, &Error{StatusCode: http.StatusNotFound, Code: "not_found", Message: "unknown operation path: " + r.URL.Path})
+	}
+}
+
+func (s *Server) createAccessToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeError(w, &Error{StatusCode: http.StatusMethodNotAllowed, Code: "method_not_allowed", Message: "create_access_token requires PUT"})
+		return
+	}
+	if r.Header.Get(vpc.MetadataFlavor) != vpc.DefaultMetadataFlavor {
+		writeError(w, &Error{StatusCode: http.StatusBadRequest, Code: "missing_metadata_flavor", Message: "the Metadata-Flavor header must be set to " + vpc.DefaultMetadataFlavor})
+		return
+	}
+	if e := s.validateVersion(r); e != nil {
+		writeError(w, e)
+		return
+	}
+
+	var body struct {
+		ExpiresIn *int `json:"expires_in"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, &Error{StatusCode: http.StatusBadRequest, Code: "bad_request", Message: "invalid request body: " + err.Error()})
+		return
+	}
+
+	if s.identityTokenErr != nil {
+		writeError(w, s.identityTokenErr)
+		return
+	}
+
+	expiresIn := 300
+	if body.ExpiresIn != nil {
+		expiresIn = *body.ExpiresIn
+	}
+	writeToken(w, s.identityToken, expiresIn)
+}
+
+func (s *Server) createIAMToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, &Error{StatusCode: http.StatusMethodNotAllowed, Code: "method_not_allowed", Message: "create_iam_token requires POST"})
+		return
+	}
+	if s.isPVS && r.Header.Get(vpc.MetadataFlavor) != vpc.DefaultMetadataFlavor {
+		writeError(w, &Error{StatusCode: http.StatusBadRequest, Code: "missing_metadata_flavor", Message: "the Metadata-Flavor header must be set to " + vpc.DefaultMetadataFlavor})
+		return
+	}
+	if e := s.validateVersion(r); e != nil {
+		writeError(w, e)
+		return
+	}
+
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") || strings.TrimPrefix(auth, "Bearer ") != s.identityToken {
+		writeError(w, &Error{StatusCode: http.StatusUnauthorized, Code: "invalid_token", Message: "the instance identity token is missing or invalid"})
+		return
+	}
+
+	var body struct {
+		TrustedProfile *TrustedProfile `json:"trusted_profile"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, &Error{StatusCode: http.StatusBadRequest, Code: "bad_request", Message: "invalid request body: " + err.Error()})
+			return
+		}
+	}
+	s.lastTrustedProfile = body.TrustedProfile
+
+	if s.iamTokenErr != nil {
+		writeError(w, s.iamTokenErr)
+		return
+	}
+
+	writeToken(w, s.iamToken, s.iamTokenLifetime)
+}
+
+func (s *Server) validateVersion(r *http.Request) *Error {
+	version := r.URL.Query().Get("version")
+	if s.isPVS {
+		if version != "" {
+			return &Error{StatusCode: http.StatusBadRequest, Code: "unsupported_query", Message: "the PowerVS metadata service does not support the version query parameter"}
+		}
+		return nil
+	}
+	if s.version != "" && version != s.version {
+		return &Error{StatusCode: http.StatusBadRequest, Code: "bad_version", Message: fmt.Sprintf("unsupported version '%s', expected '%s'", version, s.version)}
+	}
+	return nil
+}
+
+func writeToken(w http.ResponseWriter, token string, expiresIn int) {
+	now := time.Now().UTC()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - the test client going away is not an error of the emulator
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": token,
+		"created_at":   now.Format(time.RFC3339),
+		"expires_at":   now.Add(time.Duration(expiresIn) * time.Second).Format(time.RFC3339),
+		"expires_in":   expiresIn,
+	})
+}
+
+func writeError(w http.ResponseWriter, e *Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.StatusCode)
+	// #nosec G104 - the test client going away is not an error of the emulator
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"code": e.Code, "message": e.Message}},
+	})
+}